@@ -1,14 +1,14 @@
 package localsearch
 
 import (
-	"tsp-ils/models"
-	"tsp-ils/utils"
+	"tsp-common/utils"
 )
 
-// Funcion 2 opt para busqueda local
-func TwoOpt(tour []models.City) ([]models.City, float64) {
-	mejorTour := utils.CopiarTour(tour)
-	mejorCosto := utils.CalcularCostoTotal(mejorTour)
+// Funcion 2 opt para busqueda local sobre una permutacion de indices.
+// Las distancias salen de la matriz precalculada.
+func TwoOpt(tour []int, dist utils.MatrizDistancia) ([]int, float64) {
+	mejorTour := utils.CopiarPermutacion(tour)
+	mejorCosto := dist.CostoPermutacion(mejorTour)
 	mejorado := true
 	n := len(tour)
 
@@ -16,12 +16,12 @@ func TwoOpt(tour []models.City) ([]models.City, float64) {
 		mejorado = false
 		for i := 1; i < n-1; i++ {
 			for j := i + 1; j < n; j++ {
-				d1 := utils.DistanciaEuclidiana(mejorTour[i-1], mejorTour[i])
-				d2 := utils.DistanciaEuclidiana(mejorTour[j], mejorTour[(j+1)%n])
+				d1 := dist.Dist(mejorTour[i-1], mejorTour[i])
+				d2 := dist.Dist(mejorTour[j], mejorTour[(j+1)%n])
 				costoActual := d1 + d2
 
-				d3 := utils.DistanciaEuclidiana(mejorTour[i-1], mejorTour[j])
-				d4 := utils.DistanciaEuclidiana(mejorTour[i], mejorTour[(j+1)%n])
+				d3 := dist.Dist(mejorTour[i-1], mejorTour[j])
+				d4 := dist.Dist(mejorTour[i], mejorTour[(j+1)%n])
 				costoNuevo := d3 + d4
 
 				if costoNuevo < costoActual {
@@ -36,7 +36,7 @@ func TwoOpt(tour []models.City) ([]models.City, float64) {
 }
 
 // Funcion para invertir un segmento del tour
-func invertirSegmento(tour []models.City, i, j int) {
+func invertirSegmento(tour []int, i, j int) {
 	for i < j {
 		tour[i], tour[j] = tour[j], tour[i]
 		i++
@@ -1,23 +1,32 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"heuristica/tsp"
 	"math/rand"
 	"path/filepath"
-	"os"
 	"time"
+	"tsp-common/utils"
 	"tsp-ils/parser"
 	"tsp-ils/solver"
-	"tsp-ils/utils"
 )
 
 func main() {
 	rand.Seed(time.Now().UnixNano())
 
+	ventana := flag.Int("ventana", 0, "Tamaño de la ventana de reoptimizacion exacta final (0 = desactivado, 4..16)")
+	flag.Parse()
+
+	if err := tsp.ValidateWindow(*ventana); err != nil {
+		fmt.Printf("ERROR: %v\n", err)
+		return
+	}
+
 	// Ruta por defecto o por argumento
-	archivo := "../Benchmark/berlin52.tsp" 
-	if len(os.Args) > 1 {
-		archivo = os.Args[1]
+	archivo := "../Benchmark/berlin52.tsp"
+	if args := flag.Args(); len(args) > 0 {
+		archivo = args[0]
 	}
 
 	//fmt.Println("=============================================")
@@ -38,8 +47,15 @@ func main() {
 
 	start := time.Now()
 
-	// 2. Ejecutar Algoritmo
-	mejorTour, mejorCosto := solver.LocalSearch(ciudades)
+	// 2. Ejecutar Algoritmo sobre la matriz de distancias precalculada
+	dist := utils.NuevaMatrizDistancia(ciudades)
+	mejorTour, mejorCosto := solver.LocalSearch(dist, len(ciudades))
+
+	// Post-proceso opcional: reoptimización exacta por ventanas
+	if *ventana > 0 {
+		mejorTour = tsp.WindowReoptimizeFunc(mejorTour, dist.Dist, *ventana)
+		mejorCosto = dist.CostoPermutacion(mejorTour)
+	}
 
 	elapsed := time.Since(start)
 
@@ -2,28 +2,28 @@ package solver
 
 import (
 	"math/rand"
+	"tsp-common/utils"
 	"tsp-ils/localsearch"
-	"tsp-ils/models"
-	"tsp-ils/utils"
 )
 
 // LocalSearch ejecuta el algoritmo de Búsqueda
 // Genera un inicio aleatorio y aplica 2-opt hasta llegar a un óptimo local.
-func LocalSearch(ciudades []models.City) ([]models.City, float64) {
+// El tour es una permutación de índices sobre las ciudades de la matriz.
+func LocalSearch(dist utils.MatrizDistancia, n int) ([]int, float64) {
 	
 	// Solución Inicial Aleatoria
-	tourActual := utils.CopiarTour(ciudades)
+	tourActual := utils.PermutacionIdentidad(n)
 	
 	// Aleatorizamos el orden (Random Start)
 	rand.Shuffle(len(tourActual), func(i, j int) {
 		tourActual[i], tourActual[j] = tourActual[j], tourActual[i]
 	})
 
-	//costoInicial := utils.CalcularCostoTotal(tourActual)
+	//costoInicial := dist.CostoPermutacion(tourActual)
 	//fmt.Printf("   >> Costo Inicial (Aleatorio): %.4f\n", costoInicial)
 
 	// Aplicar 2-Opt
-	mejorTour, mejorCosto := localsearch.TwoOpt(tourActual)
+	mejorTour, mejorCosto := localsearch.TwoOpt(tourActual, dist)
 
 	return mejorTour, mejorCosto
 }
@@ -1,26 +1,76 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"heuristica/kopt"
+	"heuristica/tsp"
 	"math/rand"
 	"path/filepath"
-	"os"
 	"time"
+	"tsp-common/utils"
 	"tsp-ils/parser"
+	"tsp-ils/perturbation"
 	"tsp-ils/solver"
-	"tsp-ils/utils"
 )
 
 // Funcion main
 func main() {
 	rand.Seed(time.Now().UnixNano())
 
+	ls := flag.String("ls", kopt.Metodo2Opt, "Busqueda local tras cada perturbacion (2opt, 3opt, lk, clk)")
+	inicial := flag.String("init", tsp.InitialRandom, "Tour inicial (random, hilbert, sierpinski)")
+	ventana := flag.Int("ventana", 0, "Tamaño de la ventana de reoptimizacion exacta final (0 = desactivado, 4..16)")
+	iteraciones := flag.Int("iter", 3000, "Iteraciones de ILS (0 = sin limite, requiere -time)")
+	tiempo := flag.Duration("time", 0, "Tiempo maximo de ILS, por ejemplo 30s (0 = sin limite)")
+	aceptacion := flag.String("accept", solver.AceptarMejor, "Criterio de aceptacion (better, better-equal, random-walk, lsmc, restart, within)")
+	temperatura := flag.Float64("temp", 0, "Temperatura de lsmc en unidades de costo (0 = automatica)")
+	reinicio := flag.Int("restart", 100, "Iteraciones sin mejorar el mejor global antes de reiniciar (restart)")
+	umbral := flag.Float64("within", 1.0, "Porcentaje sobre el mejor global que se acepta (within)")
+	perturbacion := flag.String("perturb", perturbation.PerturbarDoubleBridge, "Perturbacion (double-bridge, local-db, reversal, segment-restart)")
+	fuerza := flag.Int("strength", 1, "Patadas por perturbacion")
+	radio := flag.Int("radius", 30, "Largo de la ventana de local-db, reversal y segment-restart")
+	adaptativa := flag.Bool("adaptive", false, "Aumentar la fuerza con el estancamiento y volver a -strength al mejorar")
+	fuerzaMax := flag.Int("max_strength", 10, "Fuerza maxima con -adaptive")
+	paciencia := flag.Int("patience", 20, "Iteraciones sin mejora por cada patada extra con -adaptive")
+	flag.Parse()
+
+	if err := kopt.ValidarMetodo(*ls); err != nil {
+		fmt.Printf("ERROR: %v\n", err)
+		return
+	}
+
+	if err := tsp.ValidateInitial(*inicial); err != nil {
+		fmt.Printf("ERROR: %v\n", err)
+		return
+	}
+
+	if err := tsp.ValidateWindow(*ventana); err != nil {
+		fmt.Printf("ERROR: %v\n", err)
+		return
+	}
+
+	if err := solver.ValidarAceptacion(*aceptacion); err != nil {
+		fmt.Printf("ERROR: %v\n", err)
+		return
+	}
+
+	if err := perturbation.ValidarPerturbacion(*perturbacion); err != nil {
+		fmt.Printf("ERROR: %v\n", err)
+		return
+	}
+
+	if *iteraciones < 0 || *tiempo < 0 || (*iteraciones == 0 && *tiempo == 0) {
+		fmt.Printf("ERROR: -iter y -time no pueden ser negativos y al menos uno debe ser positivo\n")
+		return
+	}
+
 	rutaPorDefecto := "../Benchmark/berlin52.tsp"
 	archivo := rutaPorDefecto
 
 	// Si pasas un argumento por consola, usa ese en su lugar
-	if len(os.Args) > 1 {
-		archivo = os.Args[1]
+	if args := flag.Args(); len(args) > 0 {
+		archivo = args[0]
 	}
 
 	//fmt.Println("=============================================")
@@ -42,7 +92,33 @@ func main() {
 	start := time.Now()
 
 	// 2. Ejecutar Algoritmo
-	mejorTour, mejorCosto := solver.ILS(ciudades, 3000)
+	dist := utils.NuevaMatrizDistancia(ciudades)
+	config := solver.ILSConfig{
+		MaxIteraciones: *iteraciones,
+		TiempoLimite:   *tiempo,
+		MetodoLS:       *ls,
+		Inicial:        *inicial,
+		Aceptacion:     *aceptacion,
+		Temperatura:    *temperatura,
+		Reinicio:       *reinicio,
+		Umbral:         *umbral,
+		Perturbacion: perturbation.Config{
+			Metodo:     *perturbacion,
+			Fuerza:     *fuerza,
+			Radio:      *radio,
+			Adaptativa: *adaptativa,
+			FuerzaMax:  *fuerzaMax,
+			Paciencia:  *paciencia,
+		},
+	}
+	resultado := solver.ILS(ciudades, dist, config)
+	mejorTour, mejorCosto := resultado.Tour, resultado.Costo
+
+	// Post-proceso opcional: reoptimización exacta por ventanas
+	if *ventana > 0 {
+		mejorTour = tsp.WindowReoptimizeFunc(mejorTour, dist.Dist, *ventana)
+		mejorCosto = dist.CostoPermutacion(mejorTour)
+	}
 
 	elapsed := time.Since(start)
 
@@ -88,6 +164,11 @@ func main() {
 	fmt.Printf("%s\t%.4f\n", nombreArchivo, mejorCosto)
 	fmt.Printf("%s\t%.0f\n", nombreArchivo, optimo)
 	fmt.Printf("%s\t%.2f%%\n", nombreArchivo, gap)
+
+	fmt.Printf("Iteraciones: %d, mejor en la iteracion %d (%s)\n", resultado.Iteraciones, resultado.IteracionMejor, resultado.TiempoMejor)
+	fmt.Printf("Aceptacion %s: %d mejores, %d iguales, %d peores, %d rechazados, %d reinicios\n",
+		*aceptacion, resultado.Mejores, resultado.Iguales, resultado.Peores, resultado.Rechazados, resultado.Reinicios)
+	fmt.Printf("Perturbacion %s: fuerza maxima usada %d\n", *perturbacion, resultado.FuerzaMaxima)
 	
 	fmt.Println("---------------------------------------------")
 
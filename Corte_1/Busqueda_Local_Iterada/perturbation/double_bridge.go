@@ -2,37 +2,65 @@ package perturbation
 
 import (
 	"math/rand"
-	"tsp-ils/models"
-	"tsp-ils/utils"
+	"tsp-common/utils"
 )
 
-// Funcion Double Bridge para la perturbacion
-func DoubleBridge(tour []models.City) []models.City {
+// Funcion Double Bridge para la perturbacion. Los tres cortes se eligen al
+// azar en todo el tour y el resultado se rota al azar, así que ninguna
+// arista (ni la que cierra el arreglo) tiene más chances de romperse.
+func DoubleBridge(tour []int) []int {
 	n := len(tour)
 	if n < 8 {
-		return utils.CopiarTour(tour)
+		return utils.CopiarPermutacion(tour)
 	}
 
-	indices := make([]int, 3)
-	indices[0] = rand.Intn(n/4) + 1
-	indices[1] = indices[0] + rand.Intn(n/4) + 1
-	indices[2] = indices[1] + rand.Intn(n/4) + 1
-	if indices[2] >= n {
-		indices[2] = n - 1
+	nuevoTour := rotar(tour, rand.Intn(n))
+	dobleBridge(nuevoTour)
+	return nuevoTour
+}
+
+// DoubleBridgeLocal aplica un double bridge con los tres cortes dentro de una
+// ventana de radio posiciones consecutivas que empieza en una posición al
+// azar, de modo que las aristas nuevas unen ciudades cercanas en el tour y la
+// búsqueda local repara el cambio en poco tiempo.
+func DoubleBridgeLocal(tour []int, radio int) []int {
+	n := len(tour)
+	if n < 8 {
+		return utils.CopiarPermutacion(tour)
 	}
+	radio = min(max(radio, 4), n)
 
-	pos1, pos2, pos3 := indices[0], indices[1], indices[2]
+	nuevoTour := rotar(tour, rand.Intn(n))
+	dobleBridge(nuevoTour[:radio])
+	return nuevoTour
+}
 
-	A := tour[:pos1]
-	B := tour[pos1:pos2]
-	C := tour[pos2:pos3]
-	D := tour[pos3:]
+// dobleBridge corta t en A B C D con tres cortes distintos al azar (A no
+// vacío) y lo reordena en su lugar como A C B D. Requiere len(t) >= 4.
+func dobleBridge(t []int) {
+	n := len(t)
+	cortes := rand.Perm(n - 1)[:3]
+	p1, p2, p3 := cortes[0]+1, cortes[1]+1, cortes[2]+1
+	if p1 > p2 {
+		p1, p2 = p2, p1
+	}
+	if p2 > p3 {
+		p2, p3 = p3, p2
+	}
+	if p1 > p2 {
+		p1, p2 = p2, p1
+	}
 
-	nuevoTour := make([]models.City, 0, n)
-	nuevoTour = append(nuevoTour, A...)
-	nuevoTour = append(nuevoTour, D...)
-	nuevoTour = append(nuevoTour, C...)
-	nuevoTour = append(nuevoTour, B...)
+	// A y D quedan en su lugar; B y C intercambian posiciones
+	bc := make([]int, 0, p3-p1)
+	bc = append(bc, t[p2:p3]...)
+	bc = append(bc, t[p1:p2]...)
+	copy(t[p1:p3], bc)
+}
 
-	return nuevoTour
+// rotar devuelve una copia de tour que empieza en la posición inicio
+func rotar(tour []int, inicio int) []int {
+	nuevoTour := make([]int, 0, len(tour))
+	nuevoTour = append(nuevoTour, tour[inicio:]...)
+	return append(nuevoTour, tour[:inicio]...)
 }
@@ -0,0 +1,94 @@
+package perturbation
+
+import "fmt"
+
+// Perturbaciones seleccionables con la opción -perturb.
+const (
+	PerturbarDoubleBridge = "double-bridge"   // Double bridge con cortes en todo el tour
+	PerturbarLocal        = "local-db"        // Double bridge con cortes dentro de una ventana
+	PerturbarInversion    = "reversal"        // Inversión de segmentos al azar
+	PerturbarReinicio     = "segment-restart" // Sub-camino visitado en orden aleatorio
+)
+
+// Perturbaciones lista las perturbaciones disponibles.
+var Perturbaciones = []string{PerturbarDoubleBridge, PerturbarLocal, PerturbarInversion, PerturbarReinicio}
+
+// ValidarPerturbacion devuelve un error si el nombre no corresponde a ninguna perturbación.
+func ValidarPerturbacion(metodo string) error {
+	for _, p := range Perturbaciones {
+		if p == metodo {
+			return nil
+		}
+	}
+	return fmt.Errorf("perturbación desconocida %q (opciones: %v)", metodo, Perturbaciones)
+}
+
+// Configuración de la perturbación
+type Config struct {
+	Metodo     string // Perturbación (ver Perturbaciones); vacío = double-bridge
+	Fuerza     int    // Patadas por perturbación
+	Radio      int    // Largo de la ventana de local-db, reversal y segment-restart
+	Adaptativa bool   // Si la fuerza crece con el estancamiento
+	FuerzaMax  int    // Fuerza máxima con Adaptativa
+	Paciencia  int    // Iteraciones sin mejora por cada patada extra con Adaptativa
+}
+
+// Perturbador aplica la perturbación configurada y, si es adaptativa, lleva
+// la fuerza actual: sube en 1 cada Paciencia iteraciones sin mejora (hasta
+// FuerzaMax) y vuelve a Fuerza cuando hay mejora.
+type Perturbador struct {
+	config    Config
+	fuerza    int
+	sinMejora int
+}
+
+// NuevoPerturbador crea un perturbador con la fuerza inicial de config
+func NuevoPerturbador(config Config) *Perturbador {
+	config.Fuerza = max(config.Fuerza, 1)
+	config.FuerzaMax = max(config.FuerzaMax, config.Fuerza)
+	config.Paciencia = max(config.Paciencia, 1)
+	return &Perturbador{config: config, fuerza: config.Fuerza}
+}
+
+// Fuerza devuelve la cantidad de patadas que aplicará la próxima perturbación
+func (p *Perturbador) Fuerza() int {
+	return p.fuerza
+}
+
+// Perturbar devuelve un tour nuevo con Fuerza patadas aplicadas sobre tour
+func (p *Perturbador) Perturbar(tour []int) []int {
+	switch p.config.Metodo {
+	case PerturbarInversion:
+		return InvertirSegmentos(tour, p.fuerza, p.config.Radio)
+	case PerturbarLocal:
+		for k := 0; k < p.fuerza; k++ {
+			tour = DoubleBridgeLocal(tour, p.config.Radio)
+		}
+	case PerturbarReinicio:
+		for k := 0; k < p.fuerza; k++ {
+			tour = ReiniciarSegmento(tour, p.config.Radio)
+		}
+	default:
+		for k := 0; k < p.fuerza; k++ {
+			tour = DoubleBridge(tour)
+		}
+	}
+	return tour
+}
+
+// Actualizar informa si la última iteración mejoró al mejor tour, para
+// ajustar la fuerza cuando la perturbación es adaptativa
+func (p *Perturbador) Actualizar(mejoro bool) {
+	if !p.config.Adaptativa {
+		return
+	}
+	if mejoro {
+		p.fuerza, p.sinMejora = p.config.Fuerza, 0
+		return
+	}
+	p.sinMejora++
+	if p.sinMejora >= p.config.Paciencia && p.fuerza < p.config.FuerzaMax {
+		p.fuerza++
+		p.sinMejora = 0
+	}
+}
@@ -0,0 +1,46 @@
+package perturbation
+
+import (
+	"math/rand"
+	"tsp-common/utils"
+)
+
+// InvertirSegmentos aplica k patadas de inversión: en cada una invierte un
+// segmento al azar de entre 2 y radio ciudades (un movimiento 2-opt
+// aleatorio acotado).
+func InvertirSegmentos(tour []int, k, radio int) []int {
+	n := len(tour)
+	nuevoTour := utils.CopiarPermutacion(tour)
+	if n < 4 {
+		return nuevoTour
+	}
+	radio = min(max(radio, 2), n-1)
+
+	for ; k > 0; k-- {
+		largo := 2 + rand.Intn(radio-1)
+		inicio := rand.Intn(n)
+		for a, b := 0, largo-1; a < b; a, b = a+1, b-1 {
+			i, j := (inicio+a)%n, (inicio+b)%n
+			nuevoTour[i], nuevoTour[j] = nuevoTour[j], nuevoTour[i]
+		}
+	}
+	return nuevoTour
+}
+
+// ReiniciarSegmento reinicia un sub-camino: toma largo ciudades consecutivas
+// a partir de una posición al azar y las visita en un orden aleatorio. El
+// resto del tour no cambia.
+func ReiniciarSegmento(tour []int, largo int) []int {
+	n := len(tour)
+	if n < 4 {
+		return utils.CopiarPermutacion(tour)
+	}
+	largo = min(max(largo, 3), n)
+
+	nuevoTour := rotar(tour, rand.Intn(n))
+	segmento := nuevoTour[:largo]
+	rand.Shuffle(largo, func(i, j int) {
+		segmento[i], segmento[j] = segmento[j], segmento[i]
+	})
+	return nuevoTour
+}
@@ -0,0 +1,51 @@
+package solver
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// Criterios de aceptación seleccionables con la opción -accept.
+const (
+	AceptarMejor      = "better"       // Solo candidatos estrictamente mejores que el actual
+	AceptarMejorIgual = "better-equal" // Candidatos mejores o iguales que el actual
+	AceptarSiempre    = "random-walk"  // Cualquier candidato (random walk)
+	AceptarLSMC       = "lsmc"         // Peores con probabilidad exp(-delta/T) (Large-Step Markov Chain)
+	AceptarReinicio   = "restart"      // Mejores, y reinicio tras k iteraciones sin mejorar el mejor global
+	AceptarUmbral     = "within"       // Candidatos a menos de x% del mejor global
+)
+
+// Aceptaciones lista los criterios de aceptación disponibles.
+var Aceptaciones = []string{AceptarMejor, AceptarMejorIgual, AceptarSiempre, AceptarLSMC, AceptarReinicio, AceptarUmbral}
+
+// ValidarAceptacion devuelve un error si el nombre no corresponde a ningún criterio de aceptación.
+func ValidarAceptacion(criterio string) error {
+	for _, c := range Aceptaciones {
+		if c == criterio {
+			return nil
+		}
+	}
+	return fmt.Errorf("criterio de aceptación desconocido %q (opciones: %v)", criterio, Aceptaciones)
+}
+
+// aceptar decide si el candidato reemplaza al tour actual según el criterio
+// de config; temperatura es la de LSMC ya resuelta (ver ILS). Un nombre vacío
+// equivale a AceptarMejor.
+func aceptar(config ILSConfig, temperatura, costoCandidato, costoActual, costoBest float64) bool {
+	switch config.Aceptacion {
+	case AceptarMejorIgual:
+		return costoCandidato <= costoActual
+	case AceptarSiempre:
+		return true
+	case AceptarLSMC:
+		if costoCandidato < costoActual {
+			return true
+		}
+		return rand.Float64() < math.Exp(-(costoCandidato-costoActual)/temperatura)
+	case AceptarUmbral:
+		return costoCandidato <= costoBest*(1+config.Umbral/100)
+	default:
+		return costoCandidato < costoActual
+	}
+}
@@ -1,51 +1,136 @@
 package solver
 
 import (
-	"math/rand"
-	"tsp-ils/localsearch"
-	"tsp-ils/models"
+	"heuristica/kopt"
+	"heuristica/tsp"
+	"time"
+	"tsp-common/models"
+	"tsp-common/utils"
 	"tsp-ils/perturbation"
-	"tsp-ils/utils"
 )
 
-// Funcion busqueda local iterada
-func ILS(ciudades []models.City, maxIteraciones int) ([]models.City, float64) {
-	
+// Configuración de parámetros para ILS
+type ILSConfig struct {
+	MaxIteraciones int           // Iteraciones (perturbación + búsqueda local); 0 = sin límite
+	TiempoLimite   time.Duration // Tiempo máximo; 0 = sin límite
+	MetodoLS       string        // Búsqueda local ("2opt", "3opt", "lk" o "clk")
+	Inicial        string        // Tour de partida (ver tsp.InitialTour)
+	Aceptacion     string        // Criterio de aceptación (ver Aceptaciones)
+	Temperatura    float64       // Temperatura de LSMC en unidades de costo; 0 = automática
+	Reinicio       int           // Iteraciones sin mejorar el mejor global antes de reiniciar (restart)
+	Umbral         float64       // Porcentaje sobre el mejor global aceptado (within)
+
+	Perturbacion perturbation.Config // Perturbación y su fuerza
+}
+
+// Resultado de ILS con las estadísticas de aceptación
+type ILSResultado struct {
+	Tour           []int
+	Costo          float64
+	Iteraciones    int           // Iteraciones realizadas
+	IteracionMejor int           // Iteración en la que se encontró el mejor tour (0 = búsqueda local inicial)
+	TiempoMejor    time.Duration // Tiempo hasta el mejor tour
+	Mejores        int           // Candidatos aceptados con costo menor que el actual
+	Iguales        int           // Candidatos aceptados con el mismo costo
+	Peores         int           // Candidatos aceptados con costo mayor
+	Rechazados     int
+	Reinicios      int
+	FuerzaMaxima   int // Mayor fuerza de perturbación usada
+}
+
+// Funcion busqueda local iterada. Parte del tour config.Inicial, lo optimiza
+// con config.MetodoLS y repite perturbacion (config.Perturbacion) + busqueda local
+// hasta agotar las iteraciones o el tiempo, decidiendo con config.Aceptacion
+// si el candidato reemplaza al tour actual. Los tours son permutaciones de
+// indices sobre ciudades y dist. Tras cada perturbacion la busqueda local
+// solo reoptimiza alrededor de las ciudades tocadas (ver kopt.Reoptimizar).
+func ILS(ciudades []models.City, dist utils.MatrizDistancia, config ILSConfig) ILSResultado {
+	inicio := time.Now()
+
+	// Búsqueda local con las listas de vecinos de la instancia
+	coords := utils.Coordenadas(ciudades)
+	ls := kopt.NuevaBusquedaLocal(config.MetodoLS, coords, dist.Dist)
+
 	// Solución Inicial
-	tourActual := utils.CopiarTour(ciudades)
-	rand.Shuffle(len(tourActual), func(i, j int) {
-		tourActual[i], tourActual[j] = tourActual[j], tourActual[i]
-	})
+	tourActual := tsp.InitialTour(coords, config.Inicial)
 
 	// Búsqueda Local Inicial
-	tourActual, costoActual := localsearch.TwoOpt(tourActual)
+	tourActual, costoActual := ls.Optimizar(tourActual)
 	//fmt.Printf("   >> Costo Inicial (2-Opt puro): %.4f\n", costoActual)
 
-	tourBest := utils.CopiarTour(tourActual)
-	costoBest := costoActual
+	res := ILSResultado{
+		Tour:        utils.CopiarPermutacion(tourActual),
+		Costo:       costoActual,
+		TiempoMejor: time.Since(inicio),
+	}
+
+	// Temperatura automática de LSMC: una décima de la arista media del
+	// primer óptimo local, del orden de lo que empeora una patada
+	temperatura := config.Temperatura
+	if temperatura <= 0 && len(ciudades) > 0 {
+		temperatura = 0.1 * costoActual / float64(len(ciudades))
+	}
+
+	perturbador := perturbation.NuevoPerturbador(config.Perturbacion)
+	sinMejora := 0
 
 	// Bucle Principal
-	for iter := 1; iter <= maxIteraciones; iter++ {
-	
+	for iter := 1; config.MaxIteraciones <= 0 || iter <= config.MaxIteraciones; iter++ {
+		if config.TiempoLimite > 0 && time.Since(inicio) >= config.TiempoLimite {
+			break
+		}
+		res.Iteraciones = iter
+
 		// Perturbación
-		tourCandidato := perturbation.DoubleBridge(tourActual)
+		res.FuerzaMaxima = max(res.FuerzaMaxima, perturbador.Fuerza())
+		tourCandidato := perturbador.Perturbar(tourActual)
 
 		// Búsqueda Local
-		tourCandidato, costoCandidato := localsearch.TwoOpt(tourCandidato)
+		tourCandidato, costoCandidato := ls.Reoptimizar(tourCandidato, kopt.CiudadesTocadas(tourActual, tourCandidato))
 
 		// Criterio de Aceptación
-		if costoCandidato < costoActual {
+		if aceptar(config, temperatura, costoCandidato, costoActual, res.Costo) {
+			switch {
+			case costoCandidato < costoActual:
+				res.Mejores++
+			case costoCandidato == costoActual:
+				res.Iguales++
+			default:
+				res.Peores++
+			}
 			tourActual = tourCandidato
 			costoActual = costoCandidato
+		} else {
+			res.Rechazados++
+		}
+
+		// Actualizar mejor global
+		if costoActual < res.Costo {
+			res.Tour = utils.CopiarPermutacion(tourActual)
+			res.Costo = costoActual
+			res.IteracionMejor = iter
+			res.TiempoMejor = time.Since(inicio)
+			sinMejora = 0
+			perturbador.Actualizar(true)
+			//fmt.Printf("   [Iter %d] ¡Nueva Mejor Solución! Costo: %.4f\n", iter, res.Costo)
+			continue
+		}
 
-			// Actualizar mejor global
-			if costoActual < costoBest {
-				tourBest = utils.CopiarTour(tourActual)
-				costoBest = costoActual
-				//fmt.Printf("   [Iter %d] ¡Nueva Mejor Solución! Costo: %.4f\n", iter, costoBest)
+		// Reinicio desde un tour aleatorio nuevo
+		perturbador.Actualizar(false)
+		sinMejora++
+		if config.Aceptacion == AceptarReinicio && config.Reinicio > 0 && sinMejora >= config.Reinicio {
+			tourActual, costoActual = ls.Optimizar(tsp.InitialTour(coords, tsp.InitialRandom))
+			res.Reinicios++
+			sinMejora = 0
+			if costoActual < res.Costo {
+				res.Tour = utils.CopiarPermutacion(tourActual)
+				res.Costo = costoActual
+				res.IteracionMejor = iter
+				res.TiempoMejor = time.Since(inicio)
 			}
 		}
 	}
 
-	return tourBest, costoBest
+	return res
 }
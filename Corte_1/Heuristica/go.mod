@@ -1,3 +1,7 @@
 module heuristica
 
-go 1.21
+go 1.23
+
+require tsp-common v0.0.0
+
+replace tsp-common => ../../Corte_2/common
@@ -0,0 +1,309 @@
+package kopt
+
+import (
+	"math/rand"
+	"sort"
+)
+
+// LKConfig agrupa los parámetros de la búsqueda Lin-Kernighan.
+type LKConfig struct {
+	Vecinos     int // Candidatos por ciudad para las aristas que se agregan
+	Profundidad int // Máximo de intercambios secuenciales por intento (k del k-opt)
+	Amplitud    int // Alternativas probadas en el primer nivel (backtracking)
+	Kicks       int // Patadas double-bridge de Chained LK (0 = solo LK)
+
+	// Listas de Vecinos candidatos precalculadas (ver ListasVecinos); si es
+	// nil se construyen en cada llamada en O(n^2 * Vecinos)
+	Candidatos [][]int
+}
+
+// LKPorDefecto es la configuración usada por LinKernighan.
+var LKPorDefecto = LKConfig{Vecinos: 8, Profundidad: 50, Amplitud: 5}
+
+// LinKernighan aplica LK con la configuración por defecto.
+// Recibe una permutación de índices, igual que ThreeOpt.
+func LinKernighan(tour []int, dist func(a, b int) float64) ([]int, float64) {
+	return LinKernighanConConfig(tour, dist, LKPorDefecto)
+}
+
+// ChainedLK aplica LK y luego itera patadas double-bridge locales seguidas de
+// LK, conservando el resultado solo si mejora (Chained Lin-Kernighan).
+func ChainedLK(tour []int, dist func(a, b int) float64, kicks int) ([]int, float64) {
+	config := LKPorDefecto
+	config.Kicks = kicks
+	return LinKernighanConConfig(tour, dist, config)
+}
+
+// LinKernighanConConfig ejecuta la búsqueda de profundidad variable: desde cada
+// ciudad t1 construye una secuencia de 2-opt encadenados mientras la ganancia
+// parcial sea positiva y se queda con el prefijo de mayor ganancia al cerrar.
+func LinKernighanConConfig(tour []int, dist func(a, b int) float64, config LKConfig) ([]int, float64) {
+	n := len(tour)
+	if n < 8 {
+		// Con tan pocas ciudades basta la vecindad 3-opt completa
+		return ThreeOptConConfig(tour, dist, ThreeOptConfig{PrimeraMejora: true})
+	}
+
+	t := NewTour(tour)
+	LinKernighanTour(t, dist, config)
+
+	mejorTour := t.Sequence()
+	return mejorTour, Costo(mejorTour, dist)
+}
+
+// LinKernighanTour aplica LK (y Chained LK si config.Kicks > 0) sobre t en su
+// lugar y devuelve el costo final.
+func LinKernighanTour(t Tour, dist func(a, b int) float64, config LKConfig) float64 {
+	return linKernighanDesde(t, dist, config, nil)
+}
+
+// linKernighanDesde es LinKernighanTour intentando mejoras solo desde las
+// ciudades de desde (todas si es nil): si t ya era un óptimo local salvo
+// alrededor de ellas, la cola de LK alcanza para reoptimizarlo.
+func linKernighanDesde(t Tour, dist distancia, config LKConfig, desde []int) float64 {
+	n := t.Len()
+	vecinos := config.Candidatos
+	if vecinos == nil {
+		vecinos = construirVecinos(n, config.Vecinos, dist)
+	}
+	lk := &linKernighan{
+		t:           t,
+		dist:        dist,
+		vecinos:     vecinos,
+		profundidad: config.Profundidad,
+		amplitud:    config.Amplitud,
+		enCola:      make([]bool, n),
+		costo:       costoTour(t, dist),
+	}
+	if lk.amplitud < 1 {
+		lk.amplitud = 1
+	}
+
+	if desde == nil {
+		for c := 0; c < n; c++ {
+			lk.encolar(c)
+		}
+	}
+	for _, c := range desde {
+		lk.encolar(c)
+	}
+	lk.optimizar()
+
+	if config.Kicks > 0 {
+		lk.encadenar(config.Kicks)
+	}
+	return lk.costo
+}
+
+// candidatoLK es un par (t3, t4): se agrega la arista (t2,t3) y se quita (t3,t4).
+type candidatoLK struct {
+	t3, t4  int
+	puntaje float64 // d(t3,t4) - d(t2,t3): ganancia del paso (lookahead)
+}
+
+type linKernighan struct {
+	t           Tour
+	dist        distancia
+	vecinos     [][]int
+	profundidad int
+	amplitud    int
+	costo       float64
+
+	// Cola de ciudades cuyo entorno cambió y deben volver a intentarse
+	cola   []int
+	enCola []bool
+
+	// Estado del intento actual
+	movs      [][4]int
+	agregadas [][2]int
+	quitadas  [][2]int
+
+	// Intercambios aceptados desde la última patada, para poder revertirla
+	registrar bool
+	historial [][4]int
+}
+
+func (lk *linKernighan) encolar(c int) {
+	if !lk.enCola[c] {
+		lk.enCola[c] = true
+		lk.cola = append(lk.cola, c)
+	}
+}
+
+// optimizar procesa la cola hasta que ninguna ciudad produzca mejora.
+func (lk *linKernighan) optimizar() {
+	for len(lk.cola) > 0 {
+		t1 := lk.cola[0]
+		lk.cola = lk.cola[1:]
+		lk.enCola[t1] = false
+
+		for lk.mejorarDesde(t1) {
+		}
+	}
+}
+
+// mejorarDesde intenta una secuencia de mejora que comience quitando una de
+// las dos aristas del tour que tocan a t1.
+func (lk *linKernighan) mejorarDesde(t1 int) bool {
+	for _, t2 := range [2]int{lk.t.Next(t1), lk.t.Prev(t1)} {
+		g := lk.dist(t1, t2)
+		lk.quitadas = append(lk.quitadas[:0], [2]int{t1, t2})
+		lk.agregadas = lk.agregadas[:0]
+		for _, cand := range lk.candidatos(t1, t2, g, lk.amplitud) {
+			if lk.profundizar(t1, t2, cand, g) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// candidatos devuelve hasta 'limite' pares (t3,t4) válidos para el siguiente
+// paso, ordenados por ganancia del paso. Se exige ganancia parcial positiva
+// y que no se agreguen aristas quitadas ni se quiten aristas agregadas.
+func (lk *linKernighan) candidatos(t1, t2 int, g float64, limite int) []candidatoLK {
+	adelante := lk.t.Next(t1) == t2
+	var lista []candidatoLK
+
+	for _, t3 := range lk.vecinos[t2] {
+		d23 := lk.dist(t2, t3)
+		if g-d23 <= 0 {
+			break // los vecinos están ordenados por distancia
+		}
+		if t3 == t1 {
+			continue
+		}
+		var t4 int
+		if adelante {
+			t4 = lk.t.Prev(t3)
+		} else {
+			t4 = lk.t.Next(t3)
+		}
+		if t4 == t2 || contieneArista(lk.quitadas, t2, t3) || contieneArista(lk.agregadas, t3, t4) {
+			continue
+		}
+		lista = append(lista, candidatoLK{t3: t3, t4: t4, puntaje: lk.dist(t3, t4) - d23})
+	}
+
+	sort.Slice(lista, func(i, j int) bool { return lista[i].puntaje > lista[j].puntaje })
+	if len(lista) > limite {
+		lista = lista[:limite]
+	}
+	return lista
+}
+
+// profundizar aplica la secuencia de intercambios que empieza en cand y
+// conserva el prefijo con mayor ganancia al cerrar el tour. Devuelve true si
+// el tour mejoró.
+func (lk *linKernighan) profundizar(t1, t2 int, cand candidatoLK, g float64) bool {
+	lk.movs = lk.movs[:0]
+	lk.quitadas = lk.quitadas[:1]
+	lk.agregadas = lk.agregadas[:0]
+	mejorGanancia := 1e-7
+	mejorLargo := 0
+
+	for paso := 0; paso < lk.profundidad; paso++ {
+		t3, t4 := cand.t3, cand.t4
+		flip2Opt(lk.t, t1, t2, t4, t3)
+		lk.movs = append(lk.movs, [4]int{t1, t2, t4, t3})
+		lk.agregadas = append(lk.agregadas, [2]int{t2, t3})
+		lk.quitadas = append(lk.quitadas, [2]int{t3, t4})
+
+		g += cand.puntaje
+		if cierre := g - lk.dist(t4, t1); cierre > mejorGanancia {
+			mejorGanancia = cierre
+			mejorLargo = len(lk.movs)
+		}
+
+		t2 = t4
+		siguientes := lk.candidatos(t1, t2, g, 1)
+		if len(siguientes) == 0 {
+			break
+		}
+		cand = siguientes[0]
+	}
+
+	// Deshacer los intercambios posteriores al mejor prefijo (en orden inverso)
+	for len(lk.movs) > mejorLargo {
+		m := lk.movs[len(lk.movs)-1]
+		lk.movs = lk.movs[:len(lk.movs)-1]
+		flip2Opt(lk.t, m[0], m[2], m[1], m[3])
+	}
+	if mejorLargo == 0 {
+		return false
+	}
+
+	lk.costo -= mejorGanancia
+	if lk.registrar {
+		lk.historial = append(lk.historial, lk.movs...)
+	}
+	for _, m := range lk.movs {
+		for _, c := range m {
+			lk.encolar(c)
+		}
+	}
+	return true
+}
+
+// contieneArista busca la arista no dirigida (u,v) en la lista.
+func contieneArista(aristas [][2]int, u, v int) bool {
+	for _, e := range aristas {
+		if (e[0] == u && e[1] == v) || (e[0] == v && e[1] == u) {
+			return true
+		}
+	}
+	return false
+}
+
+// encadenar ejecuta el ciclo de patadas de Chained LK: perturba con un
+// double-bridge local, reoptimiza solo alrededor de las ciudades tocadas y
+// revierte si el costo no mejora deshaciendo los intercambios en orden inverso.
+func (lk *linKernighan) encadenar(kicks int) {
+	lk.registrar = true
+	for k := 0; k < kicks; k++ {
+		lk.historial = lk.historial[:0]
+		costoPrevio := lk.costo
+
+		lk.dobleBridgeLocal()
+		lk.optimizar()
+
+		if lk.costo > costoPrevio-0.0001 {
+			for i := len(lk.historial) - 1; i >= 0; i-- {
+				m := lk.historial[i]
+				flip2Opt(lk.t, m[0], m[2], m[1], m[3])
+			}
+			lk.costo = costoPrevio
+		}
+	}
+}
+
+// dobleBridgeLocal intercambia dos segmentos consecutivos cortos (A B -> B A),
+// es decir, un double-bridge con todos sus cortes dentro de una misma zona.
+func (lk *linKernighan) dobleBridgeLocal() {
+	n := lk.t.Len()
+	maxLargo := n / 4
+	if maxLargo > 50 {
+		maxLargo = 50
+	}
+	l1 := rand.Intn(maxLargo) + 1
+	l2 := rand.Intn(maxLargo) + 1
+	p := rand.Intn(n)
+
+	a1 := lk.t.Next(p)
+	aL := avanzar(lk.t, a1, l1-1)
+	b1 := lk.t.Next(aL)
+	bL := avanzar(lk.t, b1, l2-1)
+	q := lk.t.Next(bL)
+
+	lk.costo += lk.dist(p, b1) + lk.dist(bL, a1) + lk.dist(aL, q) -
+		lk.dist(p, a1) - lk.dist(aL, b1) - lk.dist(bL, q)
+
+	for _, m := range flipsReconexion(reconexionIntercambio, p, a1, aL, b1, bL, q) {
+		flip2Opt(lk.t, m[0], m[1], m[2], m[3])
+		lk.historial = append(lk.historial, m)
+	}
+
+	for _, c := range [6]int{p, a1, aL, b1, bL, q} {
+		lk.encolar(c)
+	}
+}
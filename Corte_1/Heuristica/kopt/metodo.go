@@ -0,0 +1,131 @@
+package kopt
+
+import (
+	"fmt"
+	"tsp-common/utils"
+)
+
+// Nombres de las búsquedas locales seleccionables con la opción -ls de los
+// CLIs. MetodoNinguno deja el tour sin modificar; no está en Metodos porque
+// solo lo ofrecen los CLIs en los que la búsqueda local es opcional.
+const (
+	MetodoNinguno   = "none"
+	Metodo2Opt      = "2opt"
+	Metodo3Opt      = "3opt"
+	MetodoLK        = "lk"
+	MetodoChainedLK = "clk"
+)
+
+// KicksChainedLK es la cantidad de patadas usada por MetodoChainedLK.
+var KicksChainedLK = 100
+
+// Vecinos2Opt es el largo de las listas de vecinos de 2-opt en las instancias
+// de más de utils.MaxCiudadesMatriz ciudades, en las que la vecindad completa
+// (O(n^2) distancias por pasada, sin matriz) es demasiado lenta.
+var Vecinos2Opt = 10
+
+// Metodos lista las búsquedas locales disponibles.
+var Metodos = []string{Metodo2Opt, Metodo3Opt, MetodoLK, MetodoChainedLK}
+
+// ValidarMetodo devuelve un error si el nombre no corresponde a ninguna búsqueda local.
+func ValidarMetodo(metodo string) error {
+	for _, m := range Metodos {
+		if m == metodo {
+			return nil
+		}
+	}
+	return fmt.Errorf("búsqueda local desconocida %q (opciones: %v)", metodo, Metodos)
+}
+
+// BusquedaLocal aplica una de las búsquedas locales del paquete a
+// permutaciones de una misma instancia. Las listas de vecinos de 3-opt y LK
+// (y de 2-opt en instancias grandes, ver Vecinos2Opt) se construyen una sola
+// vez, con el k-d tree, y se comparten entre llamadas: los algoritmos que
+// optimizan un hijo por iteración no las recalculan.
+type BusquedaLocal struct {
+	metodo  string
+	dist    distancia
+	vecinos [][]int
+}
+
+// NuevaBusquedaLocal prepara el método indicado por nombre (vacío = 2-opt)
+// para la instancia de coordenadas coords. dist es la distancia entre
+// ciudades por índice; conviene pasar la de una matriz precalculada cuando
+// la hay.
+func NuevaBusquedaLocal(metodo string, coords [][2]float64, dist func(a, b int) float64) *BusquedaLocal {
+	b := &BusquedaLocal{metodo: metodo, dist: dist}
+	switch metodo {
+	case "", Metodo2Opt:
+		if len(coords) > utils.MaxCiudadesMatriz {
+			b.vecinos = ListasVecinos(coords, Vecinos2Opt)
+		}
+	case Metodo3Opt:
+		b.vecinos = ListasVecinos(coords, ThreeOptPorDefecto.Vecinos)
+	case MetodoLK, MetodoChainedLK:
+		b.vecinos = ListasVecinos(coords, LKPorDefecto.Vecinos)
+	}
+	return b
+}
+
+// Optimizar aplica la búsqueda local sobre tour y devuelve el tour mejorado
+// con su costo.
+func (b *BusquedaLocal) Optimizar(tour []int) ([]int, float64) {
+	return b.Reoptimizar(tour, nil)
+}
+
+// Reoptimizar es Optimizar para un tour que solo dejó de ser un óptimo local
+// alrededor de las ciudades tocadas, por ejemplo tras una patada (ver
+// CiudadesTocadas): LK, Chained LK y 2-opt con listas de vecinos encolan
+// solo esas ciudades en lugar de hacer una pasada completa. 3-opt y 2-opt con
+// la vecindad completa recorren el tour entero. Con tocadas nil equivale a
+// Optimizar.
+func (b *BusquedaLocal) Reoptimizar(tour []int, tocadas []int) ([]int, float64) {
+	switch b.metodo {
+	case MetodoNinguno:
+		return tour, Costo(tour, b.dist)
+	case Metodo3Opt:
+		config := ThreeOptPorDefecto
+		config.Candidatos = b.vecinos
+		return ThreeOptConConfig(tour, b.dist, config)
+	case MetodoLK, MetodoChainedLK:
+		config := LKPorDefecto
+		config.Candidatos = b.vecinos
+		if b.metodo == MetodoChainedLK {
+			config.Kicks = KicksChainedLK
+		}
+		if tocadas == nil || len(tour) < 8 {
+			return LinKernighanConConfig(tour, b.dist, config)
+		}
+		t := NewTour(tour)
+		linKernighanDesde(t, b.dist, config, tocadas)
+		mejorTour := t.Sequence()
+		return mejorTour, Costo(mejorTour, b.dist)
+	default:
+		if b.vecinos == nil {
+			return TwoOpt(tour, b.dist)
+		}
+		t := NewTour(tour)
+		twoOptVecinos(t, b.dist, b.vecinos, tocadas)
+		mejorTour := t.Sequence()
+		return mejorTour, Costo(mejorTour, b.dist)
+	}
+}
+
+// CiudadesTocadas devuelve las ciudades de despues que tienen alguna arista
+// que no estaba en antes (dos permutaciones de las mismas ciudades), es
+// decir, los extremos de las aristas que cambió una perturbación.
+func CiudadesTocadas(antes, despues []int) []int {
+	n := len(antes)
+	siguiente := make([]int, n)
+	for i, c := range antes {
+		siguiente[c] = antes[(i+1)%n]
+	}
+	var tocadas []int
+	for i, u := range despues {
+		v := despues[(i+1)%n]
+		if siguiente[u] != v && siguiente[v] != u {
+			tocadas = append(tocadas, u, v)
+		}
+	}
+	return tocadas
+}
@@ -0,0 +1,30 @@
+package kopt
+
+import "math/rand"
+
+// DobleBridge aplica la patada double-bridge sobre t en su lugar: corta el
+// tour después de tres ciudades al azar x, y, z y reordena A B C D como
+// A C B D. Se realiza con tres flip2Opt, así que cuesta lo mismo que tres
+// inversiones de la representación usada.
+func DobleBridge(t Tour) {
+	n := t.Len()
+	if n < 8 {
+		return
+	}
+
+	x, y, z := rand.Intn(n), rand.Intn(n), rand.Intn(n)
+	for y == x {
+		y = rand.Intn(n)
+	}
+	for z == x || z == y {
+		z = rand.Intn(n)
+	}
+	// Ordenar los cortes según el recorrido a partir de x
+	if !t.Between(x, y, z) {
+		y, z = z, y
+	}
+
+	for _, m := range flipsReconexion(reconexionIntercambio, x, t.Next(x), y, t.Next(y), z, t.Next(z)) {
+		flip2Opt(t, m[0], m[1], m[2], m[3])
+	}
+}
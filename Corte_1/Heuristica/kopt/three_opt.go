@@ -0,0 +1,208 @@
+package kopt
+
+// ThreeOptConfig agrupa los parámetros de la búsqueda local 3-opt.
+type ThreeOptConfig struct {
+	Vecinos       int  // Vecinos cercanos por ciudad (0 = vecindad completa, O(n^3) por pasada)
+	PrimeraMejora bool // true: aplica la primera mejora encontrada; false: la mejor de la vecindad
+
+	// Listas de Vecinos precalculadas (ver ListasVecinos); si es nil y
+	// Vecinos > 0 se construyen en cada llamada en O(n^2 * Vecinos)
+	Candidatos [][]int
+}
+
+// ThreeOptPorDefecto usa listas de 10 vecinos y primera mejora.
+var ThreeOptPorDefecto = ThreeOptConfig{Vecinos: 10, PrimeraMejora: true}
+
+// Tipos de reconexión al quitar las aristas (a,b), (c,d), (e,f), con
+// S1 = b..c y S2 = d..e. Los tres primeros equivalen a movimientos 2-opt.
+const (
+	reconexionInvertirS1    = iota + 1 // a c..b d..e f
+	reconexionInvertirS2               // a b..c e..d f
+	reconexionInvertirAmbos            // a e..d c..b f (invierte S1+S2 completo)
+	reconexionInvertirCada             // a c..b e..d f
+	reconexionIntercambio              // a d..e b..c f ("or3opt", sin inversión)
+	reconexionS2InvS1                  // a d..e c..b f
+	reconexionInvS2S1                  // a e..d b..c f
+)
+
+// movimiento3Opt quita las aristas (a,b), (c,d), (e,f), con b = Next(a),
+// d = Next(c), f = Next(e) y c, e en ese orden a partir de b.
+type movimiento3Opt struct {
+	a, b, c, d, e, f int
+	tipo             int
+	delta            float64
+}
+
+// ThreeOpt aplica 3-opt con la configuración por defecto sobre una
+// permutación de índices, con la misma firma que TwoOpt.
+func ThreeOpt(tour []int, dist func(a, b int) float64) ([]int, float64) {
+	return ThreeOptConConfig(tour, dist, ThreeOptPorDefecto)
+}
+
+// ThreeOptConConfig aplica 3-opt hasta llegar a un óptimo local, evaluando los
+// siete tipos de reconexión de las tres aristas eliminadas.
+func ThreeOptConConfig(tour []int, dist func(a, b int) float64, config ThreeOptConfig) ([]int, float64) {
+	if len(tour) < 6 {
+		mejorTour := make([]int, len(tour))
+		copy(mejorTour, tour)
+		return mejorTour, Costo(mejorTour, dist)
+	}
+
+	t := NewTour(tour)
+	ThreeOptTour(t, dist, config)
+
+	mejorTour := t.Sequence()
+	return mejorTour, Costo(mejorTour, dist)
+}
+
+// ThreeOptTour aplica 3-opt sobre t en su lugar y devuelve el costo final.
+func ThreeOptTour(t Tour, dist func(a, b int) float64, config ThreeOptConfig) float64 {
+	if t.Len() >= 6 {
+		vecinos := config.Candidatos
+		if vecinos == nil && config.Vecinos > 0 {
+			vecinos = construirVecinos(t.Len(), config.Vecinos, dist)
+		}
+		threeOpt(t, dist, vecinos, config.PrimeraMejora)
+	}
+	return costoTour(t, dist)
+}
+
+// threeOpt modifica el tour en su lugar hasta que ningún movimiento mejore más de 0.0001.
+func threeOpt(t Tour, dist distancia, vecinos [][]int, primeraMejora bool) {
+	n := t.Len()
+
+	mejorado := true
+	for mejorado {
+		mejorado = false
+		mejor := movimiento3Opt{delta: -0.0001}
+
+		// evaluar registra el movimiento si mejora al mejor conocido. Con primera
+		// mejora lo aplica de inmediato y devuelve true para pasar a la siguiente a.
+		evaluar := func(a, c, e int) bool {
+			b := t.Next(a)
+			if c == a || e == a || c == e || !t.Between(b, c, e) {
+				return false
+			}
+			m := movimiento3Opt{a: a, b: b, c: c, d: t.Next(c), e: e, f: t.Next(e)}
+			m.tipo, m.delta = mejorReconexion(dist, m)
+			if m.delta >= mejor.delta {
+				return false
+			}
+			mejor = m
+			if !primeraMejora {
+				return false
+			}
+			aplicar3Opt(t, mejor)
+			mejor = movimiento3Opt{delta: -0.0001}
+			mejorado = true
+			return true
+		}
+
+		for a := 0; a < n; a++ {
+			b := t.Next(a)
+
+			if vecinos == nil {
+				// Vecindad completa: c recorre b..e y e recorre el resto del tour
+			completa:
+				for c := b; c != a; c = t.Next(c) {
+					for e := t.Next(c); e != a; e = t.Next(e) {
+						if evaluar(a, c, e) {
+							break completa
+						}
+					}
+				}
+				continue
+			}
+
+			// Vecindad restringida: la nueva arista que sale de a debe ir a
+			// uno de sus vecinos cercanos y ser más corta que (a,b).
+			dAB := dist(a, b)
+		restringida:
+			for _, g := range vecinos[a] {
+				if dist(a, g) >= dAB {
+					break
+				}
+				for _, h := range vecinos[b] {
+					// g = c (a-c nueva) con h = e o h = f
+					if evaluar(a, g, h) || evaluar(a, g, t.Prev(h)) {
+						break restringida
+					}
+					// g = d (a-d nueva) con h = e o h = f
+					if evaluar(a, t.Prev(g), h) || evaluar(a, t.Prev(g), t.Prev(h)) {
+						break restringida
+					}
+					// g = e (a-e nueva) con h = d
+					if evaluar(a, t.Prev(h), g) {
+						break restringida
+					}
+				}
+			}
+		}
+
+		if mejor.tipo != 0 {
+			aplicar3Opt(t, mejor)
+			mejorado = true
+		}
+	}
+}
+
+// mejorReconexion evalúa las siete reconexiones posibles y devuelve la de menor delta.
+func mejorReconexion(dist distancia, m movimiento3Opt) (int, float64) {
+	a, b, c, d, e, f := m.a, m.b, m.c, m.d, m.e, m.f
+	dAB, dCD, dEF := dist(a, b), dist(c, d), dist(e, f)
+
+	deltas := [8]float64{
+		reconexionInvertirS1:    dist(a, c) + dist(b, d) - dAB - dCD,
+		reconexionInvertirS2:    dist(c, e) + dist(d, f) - dCD - dEF,
+		reconexionInvertirAmbos: dist(a, e) + dist(b, f) - dAB - dEF,
+		reconexionInvertirCada:  dist(a, c) + dist(b, e) + dist(d, f) - dAB - dCD - dEF,
+		reconexionIntercambio:   dist(a, d) + dist(e, b) + dist(c, f) - dAB - dCD - dEF,
+		reconexionS2InvS1:       dist(a, d) + dist(e, c) + dist(b, f) - dAB - dCD - dEF,
+		reconexionInvS2S1:       dist(a, e) + dist(d, b) + dist(c, f) - dAB - dCD - dEF,
+	}
+
+	mejorTipo := reconexionInvertirS1
+	for t := reconexionInvertirS2; t <= reconexionInvS2S1; t++ {
+		if deltas[t] < deltas[mejorTipo] {
+			mejorTipo = t
+		}
+	}
+	return mejorTipo, deltas[mejorTipo]
+}
+
+// aplicar3Opt aplica la reconexión elegida como una secuencia de flip2Opt.
+func aplicar3Opt(t Tour, m movimiento3Opt) {
+	for _, f := range flipsReconexion(m.tipo, m.a, m.b, m.c, m.d, m.e, m.f) {
+		flip2Opt(t, f[0], f[1], f[2], f[3])
+	}
+}
+
+// flipsReconexion expresa cada reconexión 3-opt de a b..c d..e f como a lo
+// sumo tres flip2Opt sucesivos, independientes de la orientación del tour.
+func flipsReconexion(tipo, a, b, c, d, e, f int) [][4]int {
+	switch tipo {
+	case reconexionInvertirS1:
+		return [][4]int{{a, b, c, d}}
+	case reconexionInvertirS2:
+		return [][4]int{{c, d, e, f}}
+	case reconexionInvertirAmbos:
+		return [][4]int{{a, b, e, f}}
+	case reconexionInvertirCada:
+		return [][4]int{{a, b, c, d}, {b, d, e, f}}
+	case reconexionIntercambio:
+		return [][4]int{{a, b, e, f}, {a, e, d, c}, {e, c, b, f}}
+	case reconexionS2InvS1:
+		return [][4]int{{a, b, e, f}, {a, e, d, c}}
+	case reconexionInvS2S1:
+		return [][4]int{{a, b, e, f}, {d, c, b, f}}
+	}
+	return nil
+}
+
+// avanzar devuelve la ciudad que está k pasos después de c siguiendo Next.
+func avanzar(t Tour, c, k int) int {
+	for ; k > 0; k-- {
+		c = t.Next(c)
+	}
+	return c
+}
@@ -0,0 +1,122 @@
+// Package kopt reúne las búsquedas locales k-opt sobre permutaciones de
+// índices: 2-opt, 3-opt con listas de vecinos y Lin-Kernighan (y Chained LK).
+// Trabajan sobre la interfaz Tour, con un arreglo de posiciones o con la
+// lista de dos niveles para instancias grandes, y piden las distancias a una
+// función, así que no necesitan la matriz completa.
+package kopt
+
+// Tour es un ciclo sobre las ciudades 0..n-1 que permite consultar vecinos e
+// invertir caminos sin reescribir la permutación completa. Las búsquedas
+// locales y las perturbaciones de este paquete trabajan sobre esta interfaz.
+//
+// Reverse puede invertir el complemento del camino cuando es más corto: el
+// ciclo resultante es el mismo, pero con la orientación opuesta. Por eso los
+// movimientos se expresan con flip2Opt, que no depende de la orientación.
+type Tour interface {
+	Len() int
+	Next(c int) int
+	Prev(c int) int
+	Between(a, b, c int) bool // true si b está en el camino a..c siguiendo Next
+	Reverse(a, b int)         // invierte el camino a..b siguiendo Next
+	Sequence() []int          // permutación actual (copia)
+}
+
+// UmbralDosNiveles es la cantidad de ciudades a partir de la cual NewTour usa
+// la lista doblemente enlazada de dos niveles en lugar del arreglo.
+var UmbralDosNiveles = 10000
+
+// NewTour elige la representación según el tamaño de la instancia.
+func NewTour(orden []int) Tour {
+	if len(orden) >= UmbralDosNiveles {
+		return NewTwoLevelTour(orden)
+	}
+	return NewArrayTour(orden)
+}
+
+// ArrayTour guarda la permutación junto con el índice de posiciones de cada
+// ciudad: Next/Prev/Between en O(1) y Reverse en O(min(k, n-k)).
+type ArrayTour struct {
+	orden []int // orden[p] = ciudad en la posición p
+	pos   []int // pos[c] = posición de la ciudad c
+}
+
+// NewArrayTour crea el tour a partir de una permutación de 0..n-1.
+func NewArrayTour(orden []int) *ArrayTour {
+	t := &ArrayTour{
+		orden: make([]int, len(orden)),
+		pos:   make([]int, len(orden)),
+	}
+	copy(t.orden, orden)
+	for p, c := range t.orden {
+		t.pos[c] = p
+	}
+	return t
+}
+
+func (t *ArrayTour) Len() int { return len(t.orden) }
+
+func (t *ArrayTour) Next(c int) int {
+	return t.orden[(t.pos[c]+1)%len(t.orden)]
+}
+
+func (t *ArrayTour) Prev(c int) int {
+	n := len(t.orden)
+	return t.orden[(t.pos[c]-1+n)%n]
+}
+
+func (t *ArrayTour) Between(a, b, c int) bool {
+	return entre(t.pos[a], t.pos[b], t.pos[c])
+}
+
+// Reverse invierte el camino a..b. Si el complemento es más corto invierte
+// ese, lo que deja el mismo ciclo pero con la orientación opuesta.
+func (t *ArrayTour) Reverse(a, b int) {
+	n := len(t.orden)
+	i, j := t.pos[a], t.pos[b]
+	largo := (j-i+n)%n + 1
+	if 2*largo > n {
+		i, j = (j+1)%n, (i-1+n)%n
+		largo = n - largo
+	}
+	for k := 0; k < largo/2; k++ {
+		ci, cj := t.orden[i], t.orden[j]
+		t.orden[i], t.orden[j] = cj, ci
+		t.pos[cj], t.pos[ci] = i, j
+		i = (i + 1) % n
+		j = (j - 1 + n) % n
+	}
+}
+
+func (t *ArrayTour) Sequence() []int {
+	orden := make([]int, len(t.orden))
+	copy(orden, t.orden)
+	return orden
+}
+
+// entre indica si la posición pb está en el recorrido circular pa..pc.
+func entre(pa, pb, pc int) bool {
+	if pa <= pc {
+		return pa <= pb && pb <= pc
+	}
+	return pb >= pa || pb <= pc
+}
+
+// flip2Opt reemplaza las aristas (a,b) y (c,d) por (a,c) y (b,d). Requiere que
+// b sea el sucesor de a y d el de c en una misma orientación del tour. Se
+// deshace con flip2Opt(a, c, b, d).
+func flip2Opt(t Tour, a, b, c, d int) {
+	if t.Next(a) == b {
+		t.Reverse(b, c)
+	} else {
+		t.Reverse(a, d)
+	}
+}
+
+// costoTour recorre el ciclo sumando las aristas.
+func costoTour(t Tour, dist distancia) float64 {
+	total := 0.0
+	for c := 0; c < t.Len(); c++ {
+		total += dist(c, t.Next(c))
+	}
+	return total
+}
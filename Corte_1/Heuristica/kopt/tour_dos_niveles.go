@@ -0,0 +1,213 @@
+package kopt
+
+import "math"
+
+// segmento es un tramo contiguo del tour. Sus ciudades se guardan en orden
+// físico y el bit invertido indica si se recorren al revés.
+type segmento struct {
+	ciudades  []int
+	invertido bool
+	rango     int // posición del segmento en TwoLevelTour.segs
+}
+
+// TwoLevelTour es la lista doblemente enlazada de dos niveles (Fredman et al.):
+// el tour se parte en ~sqrt(n) segmentos con bit de inversión, de modo que
+// Reverse cuesta O(sqrt(n)) en lugar de O(n). Conviene a partir de unas
+// 10.000 ciudades; para instancias chicas ArrayTour es más rápido.
+type TwoLevelTour struct {
+	segs     []*segmento // segmentos en el orden del tour
+	seg      []*segmento // seg[c] = segmento que contiene a la ciudad c
+	idx      []int       // idx[c] = índice físico de c en seg[c].ciudades
+	tamGrupo int
+
+	// Cada corte agrega un segmento; al superar maxSegs se reconstruye
+	maxSegs int
+}
+
+// NewTwoLevelTour crea el tour a partir de una permutación de 0..n-1.
+func NewTwoLevelTour(orden []int) *TwoLevelTour {
+	n := len(orden)
+	tamGrupo := int(math.Sqrt(float64(n)))
+	if tamGrupo < 8 {
+		tamGrupo = 8
+	}
+	t := &TwoLevelTour{
+		seg:      make([]*segmento, n),
+		idx:      make([]int, n),
+		tamGrupo: tamGrupo,
+	}
+	t.construir(orden)
+	return t
+}
+
+// construir reparte la permutación en segmentos de tamGrupo ciudades.
+func (t *TwoLevelTour) construir(orden []int) {
+	n := len(orden)
+	t.segs = t.segs[:0]
+	for inicio := 0; inicio < n; inicio += t.tamGrupo {
+		fin := inicio + t.tamGrupo
+		if fin > n {
+			fin = n
+		}
+		s := &segmento{ciudades: append([]int(nil), orden[inicio:fin]...), rango: len(t.segs)}
+		for i, c := range s.ciudades {
+			t.seg[c] = s
+			t.idx[c] = i
+		}
+		t.segs = append(t.segs, s)
+	}
+	t.maxSegs = 2*len(t.segs) + 2
+}
+
+func (t *TwoLevelTour) Len() int { return len(t.seg) }
+
+// posicion devuelve el lugar de c dentro de su segmento según la orientación.
+func (t *TwoLevelTour) posicion(c int) int {
+	s := t.seg[c]
+	if s.invertido {
+		return len(s.ciudades) - 1 - t.idx[c]
+	}
+	return t.idx[c]
+}
+
+// en devuelve la ciudad en el lugar p (según la orientación) del segmento s.
+func (s *segmento) en(p int) int {
+	if s.invertido {
+		return s.ciudades[len(s.ciudades)-1-p]
+	}
+	return s.ciudades[p]
+}
+
+func (t *TwoLevelTour) Next(c int) int {
+	s := t.seg[c]
+	if p := t.posicion(c); p+1 < len(s.ciudades) {
+		return s.en(p + 1)
+	}
+	return t.segs[(s.rango+1)%len(t.segs)].en(0)
+}
+
+func (t *TwoLevelTour) Prev(c int) int {
+	s := t.seg[c]
+	if p := t.posicion(c); p > 0 {
+		return s.en(p - 1)
+	}
+	m := len(t.segs)
+	anterior := t.segs[(s.rango-1+m)%m]
+	return anterior.en(len(anterior.ciudades) - 1)
+}
+
+// Between compara las claves rango*n + posición, que ordenan el tour igual
+// que las posiciones de ArrayTour.
+func (t *TwoLevelTour) Between(a, b, c int) bool {
+	return entre(t.clave(a), t.clave(b), t.clave(c))
+}
+
+func (t *TwoLevelTour) clave(c int) int {
+	return t.seg[c].rango*len(t.seg) + t.posicion(c)
+}
+
+// Reverse invierte el camino a..b. Si el camino cae dentro de un segmento se
+// invierte físicamente; si no, se corta en los extremos y se invierte el
+// orden de los segmentos intermedios alternando su bit de inversión.
+func (t *TwoLevelTour) Reverse(a, b int) {
+	if a == b {
+		return
+	}
+	if t.Next(b) == a {
+		return // el camino es el tour completo: solo cambia la orientación
+	}
+
+	sa, sb := t.seg[a], t.seg[b]
+	if sa == sb {
+		pa, pb := t.posicion(a), t.posicion(b)
+		if pa <= pb {
+			t.invertirDentro(sa, pa, pb)
+			return
+		}
+		// a..b da toda la vuelta: el complemento Next(b)..Prev(a) está dentro
+		// del segmento y es equivalente invertirlo a él.
+		t.invertirDentro(sa, pb+1, pa-1)
+		return
+	}
+
+	t.cortarAntes(a)
+	t.cortarAntes(t.Next(b))
+	t.invertirSegmentos(t.seg[a].rango, t.seg[b].rango)
+
+	if len(t.segs) > t.maxSegs {
+		t.construir(t.Sequence())
+	}
+}
+
+// invertirDentro invierte los lugares pi..pj (según la orientación) de s.
+func (t *TwoLevelTour) invertirDentro(s *segmento, pi, pj int) {
+	if s.invertido {
+		pi, pj = len(s.ciudades)-1-pj, len(s.ciudades)-1-pi
+	}
+	for ; pi < pj; pi, pj = pi+1, pj-1 {
+		ci, cj := s.ciudades[pi], s.ciudades[pj]
+		s.ciudades[pi], s.ciudades[pj] = cj, ci
+		t.idx[cj], t.idx[ci] = pi, pj
+	}
+}
+
+// cortarAntes parte el segmento de c para que c quede primero en el suyo.
+// La parte desde c pasa a un segmento nuevo insertado a continuación.
+func (t *TwoLevelTour) cortarAntes(c int) {
+	s := t.seg[c]
+	p := t.posicion(c)
+	if p == 0 {
+		return
+	}
+	if s.invertido {
+		invertirPermutacion(s.ciudades)
+		s.invertido = false
+		for i, x := range s.ciudades {
+			t.idx[x] = i
+		}
+	}
+
+	nuevo := &segmento{ciudades: append([]int(nil), s.ciudades[p:]...)}
+	s.ciudades = s.ciudades[:p:p]
+	for i, x := range nuevo.ciudades {
+		t.seg[x] = nuevo
+		t.idx[x] = i
+	}
+
+	t.segs = append(t.segs, nil)
+	copy(t.segs[s.rango+2:], t.segs[s.rango+1:])
+	t.segs[s.rango+1] = nuevo
+	for r := s.rango + 1; r < len(t.segs); r++ {
+		t.segs[r].rango = r
+	}
+}
+
+// invertirSegmentos invierte el recorrido circular de segmentos ri..rj,
+// eligiendo el lado más corto igual que ArrayTour.Reverse.
+func (t *TwoLevelTour) invertirSegmentos(ri, rj int) {
+	m := len(t.segs)
+	largo := (rj-ri+m)%m + 1
+	if 2*largo > m {
+		ri, rj = (rj+1)%m, (ri-1+m)%m
+		largo = m - largo
+	}
+	for k := 0; k < largo; k++ {
+		t.segs[(ri+k)%m].invertido = !t.segs[(ri+k)%m].invertido
+	}
+	for k := 0; k < largo/2; k++ {
+		t.segs[ri], t.segs[rj] = t.segs[rj], t.segs[ri]
+		t.segs[ri].rango, t.segs[rj].rango = ri, rj
+		ri = (ri + 1) % m
+		rj = (rj - 1 + m) % m
+	}
+}
+
+func (t *TwoLevelTour) Sequence() []int {
+	orden := make([]int, 0, len(t.seg))
+	for _, s := range t.segs {
+		for p := range s.ciudades {
+			orden = append(orden, s.en(p))
+		}
+	}
+	return orden
+}
@@ -0,0 +1,118 @@
+package kopt
+
+// TwoOpt aplica 2-opt sobre una permutación de índices hasta llegar a un
+// óptimo local y devuelve una copia mejorada con su costo.
+func TwoOpt(tour []int, dist func(a, b int) float64) ([]int, float64) {
+	if len(tour) < 4 {
+		mejorTour := make([]int, len(tour))
+		copy(mejorTour, tour)
+		return mejorTour, Costo(mejorTour, dist)
+	}
+
+	t := NewTour(tour)
+	TwoOptTour(t, dist)
+
+	// El costo se recalcula sobre el tour terminado para no arrastrar los
+	// errores de redondeo de las restas acumuladas.
+	mejorTour := t.Sequence()
+	return mejorTour, Costo(mejorTour, dist)
+}
+
+// TwoOptTour aplica 2-opt sobre t en su lugar y devuelve el costo final.
+// Cada movimiento invierte el lado más corto del tour.
+func TwoOptTour(t Tour, dist func(a, b int) float64) float64 {
+	n := t.Len()
+
+	mejorado := n >= 4
+	for mejorado {
+		mejorado = false
+		for a := 0; a < n; a++ {
+			// Cada par de aristas se evalúa una sola vez (c > a)
+			for c := a + 1; c < n; c++ {
+				b, d := t.Next(a), t.Next(c)
+				if c == b || d == a {
+					continue
+				}
+				if dist(a, b)+dist(c, d)-dist(a, c)-dist(b, d) > 0.0001 {
+					flip2Opt(t, a, b, c, d)
+					mejorado = true
+				}
+			}
+		}
+	}
+	return costoTour(t, dist)
+}
+
+// twoOptVecinos aplica 2-opt restringido a listas de vecinos sobre t en su
+// lugar: desde cada ciudad a de la cola solo se prueban las aristas nuevas
+// (a,c) con c entre los vecinos de a más cercanos que su sucesor (o su
+// predecesor). Los extremos de cada movimiento aplicado vuelven a la cola, así
+// que una pasada cuesta O(n * k) y, partiendo de un óptimo local, basta
+// encolar las ciudades tocadas (desde; todas si es nil).
+func twoOptVecinos(t Tour, dist distancia, vecinos [][]int, desde []int) {
+	n := t.Len()
+	if n < 4 {
+		return
+	}
+	enCola := make([]bool, n)
+	var cola []int
+	encolar := func(c int) {
+		if !enCola[c] {
+			enCola[c] = true
+			cola = append(cola, c)
+		}
+	}
+	if desde == nil {
+		for c := 0; c < n; c++ {
+			encolar(c)
+		}
+	}
+	for _, c := range desde {
+		encolar(c)
+	}
+
+	// mejorarDesde aplica el primer movimiento que mejora desde a
+	mejorarDesde := func(a int) bool {
+		for _, adelante := range [2]bool{true, false} {
+			b := t.Prev(a)
+			if adelante {
+				b = t.Next(a)
+			}
+			dAB := dist(a, b)
+			for _, c := range vecinos[a] {
+				dAC := dist(a, c)
+				if dAC >= dAB {
+					break // los vecinos están ordenados por distancia
+				}
+				d := t.Prev(c)
+				if adelante {
+					d = t.Next(c)
+				}
+				if c == b || d == a {
+					continue
+				}
+				if dAB+dist(c, d)-dAC-dist(b, d) <= 0.0001 {
+					continue
+				}
+				if adelante {
+					flip2Opt(t, a, b, c, d)
+				} else {
+					flip2Opt(t, b, a, d, c)
+				}
+				for _, x := range [4]int{a, b, c, d} {
+					encolar(x)
+				}
+				return true
+			}
+		}
+		return false
+	}
+
+	for len(cola) > 0 {
+		a := cola[0]
+		cola = cola[1:]
+		enCola[a] = false
+		for mejorarDesde(a) {
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package kopt
+
+import (
+	"tsp-common/kdtree"
+	"tsp-common/models"
+)
+
+// distancia devuelve el costo de la arista entre dos ciudades (por índice).
+type distancia func(a, b int) float64
+
+// ListasVecinos devuelve, para cada ciudad, sus k vecinos euclidianos más
+// cercanos ordenados de menor a mayor distancia, consultando el k-d tree de
+// tsp-common: O(n log n) esperado en lugar del O(n^2 * k) de construirVecinos.
+// Se calculan una vez por instancia y se pasan como Candidatos en LKConfig y
+// ThreeOptConfig.
+func ListasVecinos(coords [][2]float64, k int) [][]int {
+	n := len(coords)
+	if k > n-1 {
+		k = n - 1
+	}
+	arbol := kdtree.NuevoDesdeCoordenadas(coords)
+	vecinos := make([][]int, n)
+	for a, c := range coords {
+		// La consulta incluye a la propia ciudad, que se descarta
+		lista := make([]int, 0, k)
+		for _, b := range arbol.KCercanos(models.City{X: c[0], Y: c[1]}, k+1) {
+			if b != a && len(lista) < k {
+				lista = append(lista, b)
+			}
+		}
+		vecinos[a] = lista
+	}
+	return vecinos
+}
+
+// construirVecinos devuelve, para cada ciudad, sus k vecinos más cercanos
+// ordenados de menor a mayor distancia. Costo O(n^2 * k) en tiempo y O(n * k) en memoria.
+func construirVecinos(n, k int, dist distancia) [][]int {
+	if k > n-1 {
+		k = n - 1
+	}
+	vecinos := make([][]int, n)
+	dists := make([]float64, 0, k)
+
+	for a := 0; a < n; a++ {
+		lista := make([]int, 0, k)
+		dists = dists[:0]
+
+		for b := 0; b < n; b++ {
+			if b == a {
+				continue
+			}
+			d := dist(a, b)
+			if len(lista) == k && d >= dists[k-1] {
+				continue
+			}
+
+			// Inserción ordenada en la lista acotada
+			if len(lista) < k {
+				lista = append(lista, b)
+				dists = append(dists, d)
+			} else {
+				lista[k-1] = b
+				dists[k-1] = d
+			}
+			for p := len(lista) - 1; p > 0 && dists[p] < dists[p-1]; p-- {
+				lista[p], lista[p-1] = lista[p-1], lista[p]
+				dists[p], dists[p-1] = dists[p-1], dists[p]
+			}
+		}
+		vecinos[a] = lista
+	}
+	return vecinos
+}
+
+// invertirPermutacion invierte un slice de índices en su lugar.
+func invertirPermutacion(s []int) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
+
+// Costo evalúa el costo de un tour representado como permutación de índices.
+func Costo(tour []int, dist func(a, b int) float64) float64 {
+	n := len(tour)
+	if n == 0 {
+		return 0
+	}
+	total := 0.0
+	for i := 0; i < n-1; i++ {
+		total += dist(tour[i], tour[i+1])
+	}
+	total += dist(tour[n-1], tour[0])
+	return total
+}
@@ -15,12 +15,26 @@ func main() {
 	// CLI flags
 	tspFile := flag.String("tsp", "", "Path to TSPLIB .tsp file (e.g., berlin52.tsp)")
 	verbose := flag.Bool("verbose", false, "Print detailed output")
+	heuristic := flag.String("heuristic", "farthest", "Construction heuristic: nn, greedy, savings, nearest, cheapest, random, hull, farthest, double-tree, christofides, hilbert, sierpinski")
+	startCity := flag.Int("start", -1, "Start city for nn (0-based, -1 = try every start)")
+	window := flag.Int("window", 0, "Size of the final exact window reoptimization (0 = disabled, 4..16)")
 
 	flag.Parse()
 
+	if err := tsp.ValidateWindow(*window); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	algorithm, ok := heuristicNames[*heuristic]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: unknown heuristic %q (nn, greedy, savings, nearest, cheapest, random, hull, farthest, double-tree, christofides, hilbert, sierpinski)\n", *heuristic)
+		os.Exit(1)
+	}
+
 	if *tspFile == "" {
 		fmt.Fprintf(os.Stderr, "Error: must specify -tsp <file.tsp>\n")
-		fmt.Fprintf(os.Stderr, "Usage: %s -tsp <file.tsp> [-verbose]\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Usage: %s -tsp <file.tsp> [-heuristic name] [-start i] [-verbose] [-window k]\n", os.Args[0])
 		os.Exit(1)
 	}
 
@@ -31,6 +45,11 @@ func main() {
 		os.Exit(1)
 	}
 
+	if *startCity < -1 || *startCity >= inst.NumCities {
+		fmt.Fprintf(os.Stderr, "Error: start city %d out of range (0..%d, or -1 for all)\n", *startCity, inst.NumCities-1)
+		os.Exit(1)
+	}
+
 	instanceName := strings.TrimSuffix(filepath.Base(*tspFile), ".tsp")
 
 	if *verbose {
@@ -40,13 +59,19 @@ func main() {
 		} else {
 			fmt.Println("Optimal cost: unknown")
 		}
-		fmt.Printf("Algorithm: Farthest Insertion\n")
+		fmt.Printf("Algorithm: %s\n", algorithm)
+		if *window > 0 {
+			fmt.Printf("Post-processing: window reoptimization (k=%d)\n", *window)
+		}
 		fmt.Println()
 	}
 
-	// Run Farthest Insertion heuristic
+	// Run the construction heuristic
 	start := time.Now()
-	bestTour, bestLength := tsp.FarthestInsertion(inst)
+	bestTour, bestLength := construct(inst, *heuristic, *startCity)
+	if *window > 0 {
+		bestTour, bestLength = tsp.WindowReoptimize(inst, bestTour, *window)
+	}
 	elapsed := time.Since(start)
 
 	// Calculate gap
@@ -76,3 +101,52 @@ func main() {
 		}
 	}
 }
+
+// heuristicNames maps the -heuristic values to the names shown with -verbose
+var heuristicNames = map[string]string{
+	"nn":           "Nearest Neighbor",
+	"greedy":       "Greedy Edge",
+	"savings":      "Clarke-Wright Savings",
+	"nearest":      "Nearest Insertion",
+	"cheapest":     "Cheapest Insertion",
+	"random":       "Random Insertion",
+	"hull":         "Convex Hull + Cheapest Insertion",
+	"farthest":     "Farthest Insertion",
+	"double-tree":  "Double Tree (MST preorder)",
+	"christofides": "Christofides",
+	"hilbert":      "Hilbert Curve",
+	"sierpinski":   "Sierpinski Curve",
+}
+
+// construct runs the heuristic selected with -heuristic
+func construct(inst *tsp.Instance, heuristic string, startCity int) ([]int, float64) {
+	switch heuristic {
+	case "nn":
+		if startCity < 0 {
+			return tsp.NearestNeighborAllStarts(inst)
+		}
+		return tsp.NearestNeighbor(inst, startCity)
+	case "greedy":
+		return tsp.GreedyEdge(inst)
+	case "savings":
+		return tsp.Savings(inst)
+	case "nearest":
+		return tsp.NearestInsertion(inst)
+	case "cheapest":
+		return tsp.CheapestInsertion(inst)
+	case "random":
+		return tsp.RandomInsertion(inst)
+	case "hull":
+		return tsp.ConvexHullInsertion(inst)
+	case "double-tree":
+		return tsp.DoubleTree(inst)
+	case "christofides":
+		return tsp.Christofides(inst)
+	case "hilbert":
+		return tsp.HilbertCurve(inst)
+	case "sierpinski":
+		return tsp.SierpinskiCurve(inst)
+	default:
+		return tsp.FarthestInsertion(inst)
+	}
+}
@@ -0,0 +1,571 @@
+// Package matching computes minimum-weight perfect matchings on complete
+// graphs, as needed by Christofides on the odd-degree vertices of the MST.
+package matching
+
+import "slices"
+
+// MinWeightPerfect returns an exact minimum-weight perfect matching of the
+// complete graph on n vertices (n even) as mate[v] = vertex matched with v.
+// Weights must be integers so that the dual updates stay exact; TSPLIB
+// EUC_2D distances already are.
+//
+// It is Edmonds' blossom algorithm for maximum-weight matching in the
+// O(n^3) formulation of Galil, run with maximum cardinality on the weights
+// maxW - w(i, j), which turns the heaviest perfect matching into the
+// lightest one.
+func MinWeightPerfect(n int, weight func(i, j int) int64) []int {
+	if n == 0 {
+		return nil
+	}
+	var maxW int64
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			maxW = max(maxW, weight(i, j))
+		}
+	}
+	edges := make([]edge, 0, n*(n-1)/2)
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			edges = append(edges, edge{i, j, maxW + 1 - weight(i, j)})
+		}
+	}
+	return newBlossom(n, edges).solve()
+}
+
+type edge struct {
+	i, j int
+	w    int64
+}
+
+// blossom holds the state of the algorithm. Vertices are 0..n-1 and
+// non-trivial blossoms n..2n-1. Edge k has endpoints 2k (its i side) and
+// 2k+1 (its j side); an endpoint p is matched to p^1.
+type blossom struct {
+	n     int
+	edges []edge
+
+	endpoint  []int   // endpoint[p] = vertex at endpoint p
+	neighbend [][]int // neighbend[v] = remote endpoints of the edges of v
+
+	mate      []int // mate[v] = remote endpoint of v's matched edge, or -1
+	label     []int // 0 = free, 1 = S, 2 = T (for top-level blossoms)
+	labelend  []int // endpoint through which the label was assigned
+	inblossom []int // top-level blossom containing vertex v
+
+	blossomparent    []int
+	blossomchilds    [][]int
+	blossombase      []int
+	blossomendps     [][]int
+	bestedge         []int
+	blossombestedges [][]int
+	unusedblossoms   []int
+
+	dualvar   []int64
+	allowedge []bool
+	queue     []int
+}
+
+func newBlossom(n int, edges []edge) *blossom {
+	var maxW int64
+	for _, e := range edges {
+		maxW = max(maxW, e.w)
+	}
+	b := &blossom{
+		n:                n,
+		edges:            edges,
+		endpoint:         make([]int, 2*len(edges)),
+		neighbend:        make([][]int, n),
+		mate:             make([]int, n),
+		label:            make([]int, 2*n),
+		labelend:         make([]int, 2*n),
+		inblossom:        make([]int, n),
+		blossomparent:    make([]int, 2*n),
+		blossomchilds:    make([][]int, 2*n),
+		blossombase:      make([]int, 2*n),
+		blossomendps:     make([][]int, 2*n),
+		bestedge:         make([]int, 2*n),
+		blossombestedges: make([][]int, 2*n),
+		dualvar:          make([]int64, 2*n),
+		allowedge:        make([]bool, len(edges)),
+	}
+	for k, e := range edges {
+		b.endpoint[2*k], b.endpoint[2*k+1] = e.i, e.j
+		b.neighbend[e.i] = append(b.neighbend[e.i], 2*k+1)
+		b.neighbend[e.j] = append(b.neighbend[e.j], 2*k)
+	}
+	for v := 0; v < n; v++ {
+		b.mate[v] = -1
+		b.inblossom[v] = v
+		b.blossombase[v] = v
+		b.dualvar[v] = maxW
+	}
+	for i := range b.labelend {
+		b.labelend[i] = -1
+		b.blossomparent[i] = -1
+		b.bestedge[i] = -1
+	}
+	for i := n; i < 2*n; i++ {
+		b.blossombase[i] = -1
+		b.unusedblossoms = append(b.unusedblossoms, i)
+	}
+	return b
+}
+
+func (b *blossom) slack(k int) int64 {
+	e := b.edges[k]
+	return b.dualvar[e.i] + b.dualvar[e.j] - 2*e.w
+}
+
+// leaves appends the vertices contained in blossom t
+func (b *blossom) leaves(t int, out []int) []int {
+	if t < b.n {
+		return append(out, t)
+	}
+	for _, s := range b.blossomchilds[t] {
+		out = b.leaves(s, out)
+	}
+	return out
+}
+
+// assignLabel labels the top-level blossom of w with t (1 = S, 2 = T),
+// reached through endpoint p, and labels the mate of a T blossom's base S
+func (b *blossom) assignLabel(w, t, p int) {
+	bw := b.inblossom[w]
+	b.label[w], b.label[bw] = t, t
+	b.labelend[w], b.labelend[bw] = p, p
+	b.bestedge[w], b.bestedge[bw] = -1, -1
+	if t == 1 {
+		b.queue = b.leaves(bw, b.queue)
+	} else {
+		base := b.blossombase[bw]
+		b.assignLabel(b.endpoint[b.mate[base]], 1, b.mate[base]^1)
+	}
+}
+
+// scanBlossom traces back from v and w to find a new blossom's base, or -1
+// if the paths reach two different roots (an augmenting path)
+func (b *blossom) scanBlossom(v, w int) int {
+	var path []int
+	base := -1
+	for v != -1 || w != -1 {
+		bv := b.inblossom[v]
+		if b.label[bv]&4 != 0 {
+			base = b.blossombase[bv]
+			break
+		}
+		path = append(path, bv)
+		b.label[bv] = 5
+		if b.labelend[bv] == -1 {
+			v = -1
+		} else {
+			v = b.endpoint[b.labelend[bv]]
+			bv = b.inblossom[v]
+			v = b.endpoint[b.labelend[bv]]
+		}
+		if w != -1 {
+			v, w = w, v
+		}
+	}
+	for _, bv := range path {
+		b.label[bv] = 1
+	}
+	return base
+}
+
+// addBlossom builds a new blossom with the given base through edge k
+func (b *blossom) addBlossom(base, k int) {
+	v, w := b.edges[k].i, b.edges[k].j
+	bb := b.inblossom[base]
+	bv := b.inblossom[v]
+	bw := b.inblossom[w]
+	nb := b.unusedblossoms[len(b.unusedblossoms)-1]
+	b.unusedblossoms = b.unusedblossoms[:len(b.unusedblossoms)-1]
+	b.blossombase[nb] = base
+	b.blossomparent[nb] = -1
+	b.blossomparent[bb] = nb
+
+	var path, endps []int
+	for bv != bb {
+		b.blossomparent[bv] = nb
+		path = append(path, bv)
+		endps = append(endps, b.labelend[bv])
+		v = b.endpoint[b.labelend[bv]]
+		bv = b.inblossom[v]
+	}
+	path = append(path, bb)
+	slices.Reverse(path)
+	slices.Reverse(endps)
+	endps = append(endps, 2*k)
+	for bw != bb {
+		b.blossomparent[bw] = nb
+		path = append(path, bw)
+		endps = append(endps, b.labelend[bw]^1)
+		w = b.endpoint[b.labelend[bw]]
+		bw = b.inblossom[w]
+	}
+	b.blossomchilds[nb] = path
+	b.blossomendps[nb] = endps
+
+	b.label[nb] = 1
+	b.labelend[nb] = b.labelend[bb]
+	b.dualvar[nb] = 0
+	for _, u := range b.leaves(nb, nil) {
+		if b.label[b.inblossom[u]] == 2 {
+			b.queue = append(b.queue, u)
+		}
+		b.inblossom[u] = nb
+	}
+
+	// Compute the least-slack edges to every S blossom
+	bestedgeto := make([]int, 2*b.n)
+	for i := range bestedgeto {
+		bestedgeto[i] = -1
+	}
+	for _, sub := range path {
+		var nblists [][]int
+		if b.blossombestedges[sub] == nil {
+			for _, u := range b.leaves(sub, nil) {
+				list := make([]int, len(b.neighbend[u]))
+				for x, p := range b.neighbend[u] {
+					list[x] = p / 2
+				}
+				nblists = append(nblists, list)
+			}
+		} else {
+			nblists = [][]int{b.blossombestedges[sub]}
+		}
+		for _, list := range nblists {
+			for _, k := range list {
+				j := b.edges[k].j
+				if b.inblossom[j] == nb {
+					j = b.edges[k].i
+				}
+				bj := b.inblossom[j]
+				if bj != nb && b.label[bj] == 1 &&
+					(bestedgeto[bj] == -1 || b.slack(k) < b.slack(bestedgeto[bj])) {
+					bestedgeto[bj] = k
+				}
+			}
+		}
+		b.blossombestedges[sub] = nil
+		b.bestedge[sub] = -1
+	}
+	var best []int
+	for _, k := range bestedgeto {
+		if k != -1 {
+			best = append(best, k)
+		}
+	}
+	b.blossombestedges[nb] = best
+	b.bestedge[nb] = -1
+	for _, k := range best {
+		if b.bestedge[nb] == -1 || b.slack(k) < b.slack(b.bestedge[nb]) {
+			b.bestedge[nb] = k
+		}
+	}
+}
+
+// expandBlossom dissolves blossom t, relabeling its children if t was a T
+// blossom in the middle of a stage
+func (b *blossom) expandBlossom(t int, endstage bool) {
+	for _, s := range b.blossomchilds[t] {
+		b.blossomparent[s] = -1
+		if s < b.n {
+			b.inblossom[s] = s
+		} else if endstage && b.dualvar[s] == 0 {
+			b.expandBlossom(s, endstage)
+		} else {
+			for _, v := range b.leaves(s, nil) {
+				b.inblossom[v] = s
+			}
+		}
+	}
+
+	if !endstage && b.label[t] == 2 {
+		childs := b.blossomchilds[t]
+		endps := b.blossomendps[t]
+		at := func(j int) int { return (j%len(childs) + len(childs)) % len(childs) }
+
+		entrychild := b.inblossom[b.endpoint[b.labelend[t]^1]]
+		j := slices.Index(childs, entrychild)
+		var jstep, endptrick int
+		if j&1 != 0 {
+			j -= len(childs)
+			jstep, endptrick = 1, 0
+		} else {
+			jstep, endptrick = -1, 1
+		}
+		p := b.labelend[t]
+		for j != 0 {
+			b.label[b.endpoint[p^1]] = 0
+			b.label[b.endpoint[endps[at(j-endptrick)]^endptrick^1]] = 0
+			b.assignLabel(b.endpoint[p^1], 2, p)
+			b.allowedge[endps[at(j-endptrick)]/2] = true
+			j += jstep
+			p = endps[at(j-endptrick)] ^ endptrick
+			b.allowedge[p/2] = true
+			j += jstep
+		}
+		bv := childs[at(j)]
+		b.label[b.endpoint[p^1]], b.label[bv] = 2, 2
+		b.labelend[b.endpoint[p^1]], b.labelend[bv] = p, p
+		b.bestedge[bv] = -1
+		j += jstep
+		for childs[at(j)] != entrychild {
+			bv = childs[at(j)]
+			if b.label[bv] == 1 {
+				j += jstep
+				continue
+			}
+			v := -1
+			for _, u := range b.leaves(bv, nil) {
+				v = u
+				if b.label[u] != 0 {
+					break
+				}
+			}
+			if b.label[v] != 0 {
+				b.label[v] = 0
+				b.label[b.endpoint[b.mate[b.blossombase[bv]]]] = 0
+				b.assignLabel(v, 2, b.labelend[v])
+			}
+			j += jstep
+		}
+	}
+
+	b.label[t], b.labelend[t] = -1, -1
+	b.blossomchilds[t], b.blossomendps[t] = nil, nil
+	b.blossombase[t] = -1
+	b.blossombestedges[t] = nil
+	b.bestedge[t] = -1
+	b.unusedblossoms = append(b.unusedblossoms, t)
+}
+
+// augmentBlossom swaps matched and unmatched edges along the even path from
+// vertex v to the base of blossom t, making v the new base
+func (b *blossom) augmentBlossom(t, v int) {
+	s := v
+	for b.blossomparent[s] != t {
+		s = b.blossomparent[s]
+	}
+	if s >= b.n {
+		b.augmentBlossom(s, v)
+	}
+	childs := b.blossomchilds[t]
+	endps := b.blossomendps[t]
+	at := func(j int) int { return (j%len(childs) + len(childs)) % len(childs) }
+
+	i := slices.Index(childs, s)
+	j := i
+	var jstep, endptrick int
+	if i&1 != 0 {
+		j -= len(childs)
+		jstep, endptrick = 1, 0
+	} else {
+		jstep, endptrick = -1, 1
+	}
+	for j != 0 {
+		j += jstep
+		s = childs[at(j)]
+		p := endps[at(j-endptrick)] ^ endptrick
+		if s >= b.n {
+			b.augmentBlossom(s, b.endpoint[p])
+		}
+		j += jstep
+		s = childs[at(j)]
+		if s >= b.n {
+			b.augmentBlossom(s, b.endpoint[p^1])
+		}
+		b.mate[b.endpoint[p]] = p ^ 1
+		b.mate[b.endpoint[p^1]] = p
+	}
+	b.blossomchilds[t] = append(slices.Clone(childs[i:]), childs[:i]...)
+	b.blossomendps[t] = append(slices.Clone(endps[i:]), endps[:i]...)
+	b.blossombase[t] = b.blossombase[b.blossomchilds[t][0]]
+}
+
+// augmentMatching flips the augmenting path through edge k
+func (b *blossom) augmentMatching(k int) {
+	v, w := b.edges[k].i, b.edges[k].j
+	for _, sp := range [2][2]int{{v, 2*k + 1}, {w, 2 * k}} {
+		s, p := sp[0], sp[1]
+		for {
+			bs := b.inblossom[s]
+			if bs >= b.n {
+				b.augmentBlossom(bs, s)
+			}
+			b.mate[s] = p
+			if b.labelend[bs] == -1 {
+				break
+			}
+			t := b.endpoint[b.labelend[bs]]
+			bt := b.inblossom[t]
+			s = b.endpoint[b.labelend[bt]]
+			j := b.endpoint[b.labelend[bt]^1]
+			if bt >= b.n {
+				b.augmentBlossom(bt, j)
+			}
+			b.mate[j] = b.labelend[bt]
+			p = b.labelend[bt] ^ 1
+		}
+	}
+}
+
+// solve runs one stage per augmentation and returns the matching
+func (b *blossom) solve() []int {
+	n := b.n
+	for stage := 0; stage < n; stage++ {
+		clear(b.label)
+		for i := range b.bestedge {
+			b.bestedge[i] = -1
+		}
+		for i := n; i < 2*n; i++ {
+			b.blossombestedges[i] = nil
+		}
+		clear(b.allowedge)
+		b.queue = b.queue[:0]
+
+		for v := 0; v < n; v++ {
+			if b.mate[v] == -1 && b.label[b.inblossom[v]] == 0 {
+				b.assignLabel(v, 1, -1)
+			}
+		}
+
+		augmented := false
+		for {
+			for len(b.queue) > 0 && !augmented {
+				v := b.queue[len(b.queue)-1]
+				b.queue = b.queue[:len(b.queue)-1]
+				for _, p := range b.neighbend[v] {
+					k := p / 2
+					w := b.endpoint[p]
+					if b.inblossom[v] == b.inblossom[w] {
+						continue
+					}
+					var kslack int64
+					if !b.allowedge[k] {
+						kslack = b.slack(k)
+						if kslack <= 0 {
+							b.allowedge[k] = true
+						}
+					}
+					if b.allowedge[k] {
+						if b.label[b.inblossom[w]] == 0 {
+							b.assignLabel(w, 2, p^1)
+						} else if b.label[b.inblossom[w]] == 1 {
+							if base := b.scanBlossom(v, w); base >= 0 {
+								b.addBlossom(base, k)
+							} else {
+								b.augmentMatching(k)
+								augmented = true
+								break
+							}
+						} else if b.label[w] == 0 {
+							b.label[w] = 2
+							b.labelend[w] = p ^ 1
+						}
+					} else if b.label[b.inblossom[w]] == 1 {
+						bv := b.inblossom[v]
+						if b.bestedge[bv] == -1 || kslack < b.slack(b.bestedge[bv]) {
+							b.bestedge[bv] = k
+						}
+					} else if b.label[w] == 0 {
+						if b.bestedge[w] == -1 || kslack < b.slack(b.bestedge[w]) {
+							b.bestedge[w] = k
+						}
+					}
+				}
+			}
+			if augmented {
+				break
+			}
+
+			// No augmenting path with the current duals: find the largest
+			// dual change that keeps them feasible
+			deltatype := -1
+			var delta int64
+			deltaedge, deltablossom := -1, -1
+			for v := 0; v < n; v++ {
+				if b.label[b.inblossom[v]] == 0 && b.bestedge[v] != -1 {
+					if d := b.slack(b.bestedge[v]); deltatype == -1 || d < delta {
+						delta, deltatype, deltaedge = d, 2, b.bestedge[v]
+					}
+				}
+			}
+			for t := 0; t < 2*n; t++ {
+				if b.blossomparent[t] == -1 && b.label[t] == 1 && b.bestedge[t] != -1 {
+					if d := b.slack(b.bestedge[t]) / 2; deltatype == -1 || d < delta {
+						delta, deltatype, deltaedge = d, 3, b.bestedge[t]
+					}
+				}
+			}
+			for t := n; t < 2*n; t++ {
+				if b.blossombase[t] >= 0 && b.blossomparent[t] == -1 && b.label[t] == 2 &&
+					(deltatype == -1 || b.dualvar[t] < delta) {
+					delta, deltatype, deltablossom = b.dualvar[t], 4, t
+				}
+			}
+			if deltatype == -1 {
+				// Maximum cardinality reached: no further augmenting path
+				deltatype = 1
+				delta = max(0, slices.Min(b.dualvar[:n]))
+			}
+
+			for v := 0; v < n; v++ {
+				switch b.label[b.inblossom[v]] {
+				case 1:
+					b.dualvar[v] -= delta
+				case 2:
+					b.dualvar[v] += delta
+				}
+			}
+			for t := n; t < 2*n; t++ {
+				if b.blossombase[t] >= 0 && b.blossomparent[t] == -1 {
+					switch b.label[t] {
+					case 1:
+						b.dualvar[t] += delta
+					case 2:
+						b.dualvar[t] -= delta
+					}
+				}
+			}
+
+			if deltatype == 1 {
+				break
+			}
+			switch deltatype {
+			case 2:
+				b.allowedge[deltaedge] = true
+				i, j := b.edges[deltaedge].i, b.edges[deltaedge].j
+				if b.label[b.inblossom[i]] == 0 {
+					i = j
+				}
+				b.queue = append(b.queue, i)
+			case 3:
+				b.allowedge[deltaedge] = true
+				b.queue = append(b.queue, b.edges[deltaedge].i)
+			case 4:
+				b.expandBlossom(deltablossom, false)
+			}
+		}
+		if !augmented {
+			break
+		}
+
+		// Expand the S blossoms whose dual reached zero
+		for t := n; t < 2*n; t++ {
+			if b.blossomparent[t] == -1 && b.blossombase[t] >= 0 && b.label[t] == 1 && b.dualvar[t] == 0 {
+				b.expandBlossom(t, true)
+			}
+		}
+	}
+
+	mate := make([]int, n)
+	for v, p := range b.mate {
+		mate[v] = -1
+		if p >= 0 {
+			mate[v] = b.endpoint[p]
+		}
+	}
+	return mate
+}
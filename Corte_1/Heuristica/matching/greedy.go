@@ -0,0 +1,59 @@
+package matching
+
+import "sort"
+
+// Greedy returns a perfect matching of the complete graph on n vertices
+// (n even) built by taking the lightest remaining pair first, for when the
+// blossom algorithm is too slow. Only the pairs of candidates (for example
+// the k nearest neighbors of each vertex) are sorted, so memory is O(nk);
+// the few vertices they leave unmatched are then paired by the same rule
+// over all their pairs. With nil candidates every pair is considered, in
+// O(n^2 log n).
+func Greedy(n int, weight func(i, j int) int64, candidates [][]int) []int {
+	mate := make([]int, n)
+	for i := range mate {
+		mate[i] = -1
+	}
+
+	var pairs []pair
+	if candidates != nil {
+		for i, list := range candidates {
+			for _, j := range list {
+				pairs = append(pairs, pair{min(i, j), max(i, j), weight(i, j)})
+			}
+		}
+		matchGreedy(mate, pairs)
+	}
+
+	var left []int
+	for v, m := range mate {
+		if m < 0 {
+			left = append(left, v)
+		}
+	}
+	pairs = pairs[:0]
+	for a, i := range left {
+		for _, j := range left[a+1:] {
+			pairs = append(pairs, pair{i, j, weight(i, j)})
+		}
+	}
+	matchGreedy(mate, pairs)
+	return mate
+}
+
+type pair struct {
+	i, j int
+	w    int64
+}
+
+// matchGreedy sorts pairs by weight and matches each one whose endpoints are
+// both still free (a repeated pair is skipped because its endpoints are
+// already matched)
+func matchGreedy(mate []int, pairs []pair) {
+	sort.Slice(pairs, func(a, b int) bool { return pairs[a].w < pairs[b].w })
+	for _, p := range pairs {
+		if mate[p.i] < 0 && mate[p.j] < 0 {
+			mate[p.i], mate[p.j] = p.j, p.i
+		}
+	}
+}
@@ -0,0 +1,133 @@
+// Package mst computes minimum spanning trees for the constructions that
+// need one (double-tree and Christofides).
+package mst
+
+import (
+	"container/heap"
+	"math"
+
+	"tsp-common/kdtree"
+	"tsp-common/models"
+)
+
+// Prim returns the minimum spanning tree of the complete graph on n
+// vertices as a parent array rooted at 0 (parent[0] = -1). It is the O(n^2)
+// array version of Prim, the best choice over a dense distance matrix.
+func Prim(n int, dist func(i, j int) float64) []int {
+	parent := make([]int, n)
+	if n == 0 {
+		return parent
+	}
+	inTree := make([]bool, n)
+	key := make([]float64, n)
+	for i := range key {
+		key[i] = math.MaxFloat64
+		parent[i] = -1
+	}
+	key[0] = 0
+
+	for k := 0; k < n; k++ {
+		u := -1
+		for v := 0; v < n; v++ {
+			if !inTree[v] && (u < 0 || key[v] < key[u]) {
+				u = v
+			}
+		}
+		inTree[u] = true
+		for v := 0; v < n; v++ {
+			if !inTree[v] {
+				if d := dist(u, v); d < key[v] {
+					key[v] = d
+					parent[v] = u
+				}
+			}
+		}
+	}
+	return parent
+}
+
+// PrimEuclidean is Prim over the Euclidean distances between coords, for
+// large n. Each tree vertex remembers its nearest outside vertex, found with
+// a k-d tree from which the tree vertices are deleted; a heap gives the
+// shortest of those candidates, which is the lightest edge leaving the tree.
+// A stale candidate (already in the tree) is just queried again.
+//
+// Since rounding distances to integers is monotone, the tree is also a
+// minimum spanning tree for the TSPLIB EUC_2D distances.
+func PrimEuclidean(coords [][2]float64) []int {
+	n := len(coords)
+	parent := make([]int, n)
+	if n == 0 {
+		return parent
+	}
+	for i := range parent {
+		parent[i] = -1
+	}
+	tree := kdtree.NuevoDesdeCoordenadas(coords)
+	inTree := make([]bool, n)
+	cand := &candidateHeap{}
+
+	// push queries the nearest outside vertex of v and queues it
+	push := func(v int) {
+		p := coords[v]
+		if c, ok := tree.Cercano(models.City{X: p[0], Y: p[1]}); ok {
+			dx, dy := coords[c][0]-p[0], coords[c][1]-p[1]
+			heap.Push(cand, candidate{from: v, to: c, dist2: dx*dx + dy*dy})
+		}
+	}
+
+	inTree[0] = true
+	tree.Eliminar(0)
+	push(0)
+	for cand.Len() > 0 {
+		e := heap.Pop(cand).(candidate)
+		if !inTree[e.to] {
+			inTree[e.to] = true
+			parent[e.to] = e.from
+			tree.Eliminar(e.to)
+			push(e.to)
+		}
+		push(e.from)
+	}
+	return parent
+}
+
+type candidate struct {
+	from, to int
+	dist2    float64
+}
+
+type candidateHeap []candidate
+
+func (h candidateHeap) Len() int           { return len(h) }
+func (h candidateHeap) Less(i, j int) bool { return h[i].dist2 < h[j].dist2 }
+func (h candidateHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *candidateHeap) Push(x any)        { *h = append(*h, x.(candidate)) }
+func (h *candidateHeap) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// Children converts a parent array into adjacency lists of children
+func Children(parent []int) [][]int {
+	children := make([][]int, len(parent))
+	for v, p := range parent {
+		if p >= 0 {
+			children[p] = append(children[p], v)
+		}
+	}
+	return children
+}
+
+// Weight returns the total weight of the tree given by parent
+func Weight(parent []int, dist func(i, j int) float64) float64 {
+	total := 0.0
+	for v, p := range parent {
+		if p >= 0 {
+			total += dist(v, p)
+		}
+	}
+	return total
+}
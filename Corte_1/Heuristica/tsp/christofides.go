@@ -0,0 +1,174 @@
+package tsp
+
+import (
+	"math"
+
+	"heuristica/matching"
+	"heuristica/mst"
+)
+
+// MaxBlossomVertices is the largest number of odd-degree MST vertices matched
+// exactly with the blossom algorithm; beyond it the matching is greedy over
+// the GreedyNeighbors nearest odd vertices of each one
+const MaxBlossomVertices = 600
+
+// mstThreshold is the size from which the MST is built with the k-d tree
+// instead of the O(n^2) scan of the distance matrix
+const mstThreshold = 5000
+
+// Christofides constructs a tour from the MST plus a minimum-weight perfect
+// matching on its odd-degree vertices, shortcutting the Euler circuit. With
+// the exact matching the tour is at most 1.5 times the optimum.
+func Christofides(inst *Instance) ([]int, float64) {
+	tour := ChristofidesFunc(inst.NumCities, inst.Dist, inst.Coords)
+	return tour, inst.TourLength(tour)
+}
+
+// DoubleTree constructs a tour by visiting the MST in preorder, which
+// shortcuts the Euler circuit of the doubled tree (at most twice the optimum)
+func DoubleTree(inst *Instance) ([]int, float64) {
+	tour := DoubleTreeFunc(inst.NumCities, inst.Dist, inst.Coords)
+	return tour, inst.TourLength(tour)
+}
+
+// spanningTree returns the MST parent array, using the k-d tree variant for
+// large instances when coordinates are available
+func spanningTree(n int, dist func(i, j int) float64, coords [][2]float64) []int {
+	if n >= mstThreshold && len(coords) == n {
+		return mst.PrimEuclidean(coords)
+	}
+	return mst.Prim(n, dist)
+}
+
+// ChristofidesFunc is the shared Christofides construction; coords may be nil
+// (then the MST always comes from dist). Distances are rounded to integers
+// for the matching.
+func ChristofidesFunc(n int, dist func(i, j int) float64, coords [][2]float64) []int {
+	if n < 3 {
+		return identity(n)
+	}
+	parent := spanningTree(n, dist, coords)
+
+	// Multigraph adjacency: tree edges plus matching edges
+	adj := make([][]int, n)
+	for v, p := range parent {
+		if p >= 0 {
+			adj[v] = append(adj[v], p)
+			adj[p] = append(adj[p], v)
+		}
+	}
+
+	var odd []int
+	for v := 0; v < n; v++ {
+		if len(adj[v])%2 == 1 {
+			odd = append(odd, v)
+		}
+	}
+	weight := func(i, j int) int64 {
+		return int64(math.Round(dist(odd[i], odd[j])))
+	}
+	var mate []int
+	if len(odd) <= MaxBlossomVertices {
+		mate = matching.MinWeightPerfect(len(odd), weight)
+	} else {
+		var oddCoords [][2]float64
+		if len(coords) == n {
+			oddCoords = make([][2]float64, len(odd))
+			for i, v := range odd {
+				oddCoords[i] = coords[v]
+			}
+		}
+		oddDist := func(i, j int) float64 { return dist(odd[i], odd[j]) }
+		candidates := neighborLists(len(odd), oddDist, oddCoords, GreedyNeighbors)
+		mate = matching.Greedy(len(odd), weight, candidates)
+	}
+	for i, j := range mate {
+		if i < j {
+			adj[odd[i]] = append(adj[odd[i]], odd[j])
+			adj[odd[j]] = append(adj[odd[j]], odd[i])
+		}
+	}
+
+	return shortcut(eulerCircuit(adj, 0), n)
+}
+
+// DoubleTreeFunc is the shared double-tree construction; coords may be nil
+func DoubleTreeFunc(n int, dist func(i, j int) float64, coords [][2]float64) []int {
+	if n < 3 {
+		return identity(n)
+	}
+	children := mst.Children(spanningTree(n, dist, coords))
+
+	tour := make([]int, 0, n)
+	stack := []int{0}
+	for len(stack) > 0 {
+		v := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		tour = append(tour, v)
+		for i := len(children[v]) - 1; i >= 0; i-- {
+			stack = append(stack, children[v][i])
+		}
+	}
+	return tour
+}
+
+// eulerCircuit returns an Euler circuit of the connected multigraph adj
+// (every degree even) starting at start, using Hierholzer's algorithm.
+// adj is consumed.
+func eulerCircuit(adj [][]int, start int) []int {
+	// used[v][k] marks the k-th edge of v as traversed; the twin entry on the
+	// other endpoint is found by matching the first unused copy
+	used := make([][]bool, len(adj))
+	for v := range adj {
+		used[v] = make([]bool, len(adj[v]))
+	}
+	ptr := make([]int, len(adj))
+	useTwin := func(u, v int) {
+		for k, w := range adj[u] {
+			if w == v && !used[u][k] {
+				used[u][k] = true
+				return
+			}
+		}
+	}
+
+	var circuit []int
+	stack := []int{start}
+	for len(stack) > 0 {
+		v := stack[len(stack)-1]
+		for ptr[v] < len(adj[v]) && used[v][ptr[v]] {
+			ptr[v]++
+		}
+		if ptr[v] == len(adj[v]) {
+			circuit = append(circuit, v)
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		w := adj[v][ptr[v]]
+		used[v][ptr[v]] = true
+		useTwin(w, v)
+		stack = append(stack, w)
+	}
+	return circuit
+}
+
+// shortcut keeps the first visit of every city of the circuit
+func shortcut(circuit []int, n int) []int {
+	seen := make([]bool, n)
+	tour := make([]int, 0, n)
+	for _, v := range circuit {
+		if !seen[v] {
+			seen[v] = true
+			tour = append(tour, v)
+		}
+	}
+	return tour
+}
+
+func identity(n int) []int {
+	perm := make([]int, n)
+	for i := range perm {
+		perm[i] = i
+	}
+	return perm
+}
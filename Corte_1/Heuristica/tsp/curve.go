@@ -0,0 +1,121 @@
+package tsp
+
+import (
+	"math"
+	"sort"
+)
+
+// curveBits is the resolution of the space-filling curves: coordinates are
+// mapped to a 2^curveBits x 2^curveBits grid
+const curveBits = 20
+
+// HilbertCurve constructs a tour by visiting the cities in Hilbert curve
+// order. O(n log n), for instances where O(n^2) constructions are too slow;
+// expect 25-50% above the optimum.
+func HilbertCurve(inst *Instance) ([]int, float64) {
+	tour := HilbertOrder(inst.Coords)
+	return tour, inst.TourLength(tour)
+}
+
+// SierpinskiCurve constructs a tour by visiting the cities in Sierpinski
+// curve order (Platzman and Bartholdi). Unlike the Hilbert curve it is
+// closed, so the edge back to the first city is short too.
+func SierpinskiCurve(inst *Instance) ([]int, float64) {
+	tour := SierpinskiOrder(inst.Coords)
+	return tour, inst.TourLength(tour)
+}
+
+// HilbertOrder returns the cities sorted by their index along the Hilbert
+// curve over the bounding square of coords
+func HilbertOrder(coords [][2]float64) []int {
+	return curveOrder(coords, hilbertIndex)
+}
+
+// SierpinskiOrder returns the cities sorted by their index along the
+// Sierpinski curve over the bounding square of coords
+func SierpinskiOrder(coords [][2]float64) []int {
+	return curveOrder(coords, sierpinskiIndex)
+}
+
+// curveOrder scales coords to the curve grid, keeping the aspect ratio, and
+// sorts the cities by index(x, y)
+func curveOrder(coords [][2]float64, index func(x, y uint64) uint64) []int {
+	n := len(coords)
+	if n == 0 {
+		return nil
+	}
+	minX, minY := coords[0][0], coords[0][1]
+	maxX, maxY := minX, minY
+	for _, c := range coords {
+		minX, maxX = min(minX, c[0]), max(maxX, c[0])
+		minY, maxY = min(minY, c[1]), max(maxY, c[1])
+	}
+	side := max(maxX-minX, maxY-minY)
+	if side == 0 {
+		side = 1
+	}
+	scale := float64(uint64(1)<<curveBits-1) / side
+
+	keys := make([]uint64, n)
+	for i, c := range coords {
+		x := uint64(math.Round((c[0] - minX) * scale))
+		y := uint64(math.Round((c[1] - minY) * scale))
+		keys[i] = index(x, y)
+	}
+	order := identity(n)
+	sort.SliceStable(order, func(a, b int) bool { return keys[order[a]] < keys[order[b]] })
+	return order
+}
+
+// hilbertIndex is the distance of cell (x, y) along the Hilbert curve that
+// fills the 2^curveBits grid
+func hilbertIndex(x, y uint64) uint64 {
+	const side = uint64(1) << curveBits
+	var d uint64
+	for s := side / 2; s > 0; s /= 2 {
+		var rx, ry uint64
+		if x&s > 0 {
+			rx = 1
+		}
+		if y&s > 0 {
+			ry = 1
+		}
+		d += s * s * ((3 * rx) ^ ry)
+		// Rotate the quadrant so the sub-curve starts where the parent did
+		if ry == 0 {
+			if rx == 1 {
+				x = side - 1 - x
+				y = side - 1 - y
+			}
+			x, y = y, x
+		}
+	}
+	return d
+}
+
+// sierpinskiIndex is the position of cell (x, y) along the Sierpinski curve
+// over the grid [0, 2^curveBits-1]^2, following Platzman and Bartholdi: each
+// step halves the current triangle and records which half holds the point
+func sierpinskiIndex(x, y uint64) uint64 {
+	const limit = uint64(1)<<curveBits - 1
+	var result uint64
+	if x > y {
+		result++
+		x, y = limit-x, limit-y
+	}
+	for loop := limit; loop > 0; loop /= 2 {
+		result += result
+		if x+y > limit {
+			result++
+			x, y = limit-y, x
+		}
+		x += x
+		y += y
+		result += result
+		if y > limit {
+			result++
+			x, y = y-limit, limit-x
+		}
+	}
+	return result
+}
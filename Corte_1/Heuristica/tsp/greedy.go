@@ -0,0 +1,179 @@
+package tsp
+
+import (
+	"sort"
+
+	"heuristica/kopt"
+)
+
+// GreedyNeighbors is the size of the nearest neighbor lists the greedy edge
+// candidates come from
+const GreedyNeighbors = 10
+
+// GreedyEdge constructs a tour by adding the shortest edges first, as long
+// as no city gets degree 3 and no subtour closes, and then joining the
+// resulting paths
+func GreedyEdge(inst *Instance) ([]int, float64) {
+	tour := GreedyEdgeFunc(inst.NumCities, inst.Dist, inst.Coords, GreedyNeighbors)
+	return tour, inst.TourLength(tour)
+}
+
+// GreedyEdgeFunc is the shared greedy edge construction. Only the edges to
+// the k nearest neighbors of each city are candidates, so memory is O(nk);
+// the lists come from a k-d tree over coords, or from scanning dist if
+// coords is nil. The paths left when the candidates run out are joined by
+// the same greedy rule over the pairs of their endpoints.
+func GreedyEdgeFunc(n int, dist func(i, j int) float64, coords [][2]float64, k int) []int {
+	if n < 3 {
+		return identity(n)
+	}
+	k = min(k, n-1)
+
+	neighbors := neighborLists(n, dist, coords, k)
+	edges := make([]edge, 0, n*k)
+	for i, list := range neighbors {
+		for _, j := range list {
+			edges = append(edges, edge{min(i, j), max(i, j), dist(i, j)})
+		}
+	}
+	sortEdges(edges)
+
+	// adj[v] holds up to two path neighbors of v (-1 = free slot)
+	adj := make([][2]int, n)
+	for v := range adj {
+		adj[v] = [2]int{-1, -1}
+	}
+	degree := make([]int, n)
+	uf := newUnionFind(n)
+	added := 0
+	addEdges := func(edges []edge) {
+		for e, prev := 0, (edge{-1, -1, 0}); e < len(edges) && added < n-1; e++ {
+			ed := edges[e]
+			if ed.i == prev.i && ed.j == prev.j {
+				continue
+			}
+			prev = ed
+			if degree[ed.i] == 2 || degree[ed.j] == 2 || !uf.union(ed.i, ed.j) {
+				continue
+			}
+			adj[ed.i][degree[ed.i]] = ed.j
+			adj[ed.j][degree[ed.j]] = ed.i
+			degree[ed.i]++
+			degree[ed.j]++
+			added++
+		}
+	}
+	addEdges(edges)
+
+	// Join the paths left with the same rule over every pair of endpoints;
+	// they are a few percent of the cities, so all pairs are affordable
+	var endpoints []int
+	for v := 0; v < n; v++ {
+		if degree[v] < 2 {
+			endpoints = append(endpoints, v)
+		}
+	}
+	edges = edges[:0]
+	for a, i := range endpoints {
+		for _, j := range endpoints[a+1:] {
+			if uf.find(i) != uf.find(j) {
+				edges = append(edges, edge{i, j, dist(i, j)})
+			}
+		}
+	}
+	sortEdges(edges)
+	addEdges(edges)
+
+	// Walk the Hamiltonian path from one of its ends
+	start := 0
+	for degree[start] == 2 {
+		start++
+	}
+	tour := make([]int, 0, n)
+	for prev, cur := -1, start; cur >= 0; {
+		tour = append(tour, cur)
+		next := adj[cur][0]
+		if next == prev {
+			next = adj[cur][1]
+		}
+		prev, cur = cur, next
+	}
+	return tour
+}
+
+type edge struct {
+	i, j int
+	d    float64
+}
+
+// sortEdges orders the edges by length, breaking ties by endpoints so that
+// duplicates end up adjacent
+func sortEdges(edges []edge) {
+	sort.Slice(edges, func(a, b int) bool {
+		if edges[a].d != edges[b].d {
+			return edges[a].d < edges[b].d
+		}
+		if edges[a].i != edges[b].i {
+			return edges[a].i < edges[b].i
+		}
+		return edges[a].j < edges[b].j
+	})
+}
+
+// neighborLists returns the k nearest neighbors of every city, from the k-d
+// tree when coordinates are available
+func neighborLists(n int, dist func(i, j int) float64, coords [][2]float64, k int) [][]int {
+	if len(coords) == n {
+		return kopt.ListasVecinos(coords, k)
+	}
+	lists := make([][]int, n)
+	others := make([]int, 0, n-1)
+	for i := 0; i < n; i++ {
+		others = others[:0]
+		for j := 0; j < n; j++ {
+			if j != i {
+				others = append(others, j)
+			}
+		}
+		sort.Slice(others, func(a, b int) bool { return dist(i, others[a]) < dist(i, others[b]) })
+		lists[i] = append([]int(nil), others[:k]...)
+	}
+	return lists
+}
+
+// unionFind tracks the path fragments so that no edge closes a subtour
+type unionFind struct {
+	parent, rank []int
+}
+
+func newUnionFind(n int) *unionFind {
+	uf := &unionFind{parent: make([]int, n), rank: make([]int, n)}
+	for i := range uf.parent {
+		uf.parent[i] = i
+	}
+	return uf
+}
+
+func (uf *unionFind) find(x int) int {
+	for uf.parent[x] != x {
+		uf.parent[x] = uf.parent[uf.parent[x]]
+		x = uf.parent[x]
+	}
+	return x
+}
+
+// union joins the sets of a and b; false if they were already the same
+func (uf *unionFind) union(a, b int) bool {
+	ra, rb := uf.find(a), uf.find(b)
+	if ra == rb {
+		return false
+	}
+	if uf.rank[ra] < uf.rank[rb] {
+		ra, rb = rb, ra
+	}
+	uf.parent[rb] = ra
+	if uf.rank[ra] == uf.rank[rb] {
+		uf.rank[ra]++
+	}
+	return true
+}
@@ -0,0 +1,63 @@
+package tsp
+
+import "sort"
+
+// ConvexHullInsertion starts from the convex hull of the cities and inserts
+// the rest by cheapest insertion
+func ConvexHullInsertion(inst *Instance) ([]int, float64) {
+	tour := CheapestInsertionFunc(inst.NumCities, inst.Dist, ConvexHull(inst.Coords))
+	return tour, inst.TourLength(tour)
+}
+
+// ConvexHull returns the indices of the hull vertices in counter-clockwise
+// order (Andrew's monotone chain, O(n log n)). Collinear points on the hull
+// edges are left out; if every point is collinear only the two extremes are
+// returned.
+func ConvexHull(coords [][2]float64) []int {
+	n := len(coords)
+	if n < 3 {
+		hull := make([]int, n)
+		for i := range hull {
+			hull[i] = i
+		}
+		return hull
+	}
+
+	idx := make([]int, n)
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.Slice(idx, func(a, b int) bool {
+		p, q := coords[idx[a]], coords[idx[b]]
+		if p[0] != q[0] {
+			return p[0] < q[0]
+		}
+		return p[1] < q[1]
+	})
+
+	// cross > 0 if o -> a -> b turns counter-clockwise
+	cross := func(o, a, b int) float64 {
+		return (coords[a][0]-coords[o][0])*(coords[b][1]-coords[o][1]) -
+			(coords[a][1]-coords[o][1])*(coords[b][0]-coords[o][0])
+	}
+
+	hull := make([]int, 0, 2*n)
+	// Lower hull
+	for _, c := range idx {
+		for len(hull) >= 2 && cross(hull[len(hull)-2], hull[len(hull)-1], c) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, c)
+	}
+	// Upper hull
+	lower := len(hull) + 1
+	for i := n - 2; i >= 0; i-- {
+		c := idx[i]
+		for len(hull) >= lower && cross(hull[len(hull)-2], hull[len(hull)-1], c) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, c)
+	}
+	// The last point repeats the first one
+	return hull[:len(hull)-1]
+}
@@ -0,0 +1,40 @@
+package tsp
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// Starting tours selectable with the -init option of ILS and SA
+const (
+	InitialRandom     = "random"
+	InitialHilbert    = "hilbert"
+	InitialSierpinski = "sierpinski"
+)
+
+// InitialTours lists the available starting tours
+var InitialTours = []string{InitialRandom, InitialHilbert, InitialSierpinski}
+
+// ValidateInitial returns an error if name is not one of InitialTours
+func ValidateInitial(name string) error {
+	for _, t := range InitialTours {
+		if t == name {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown initial tour %q (options: %v)", name, InitialTours)
+}
+
+// InitialTour builds a starting permutation of the cities at coords. The
+// curve orderings run in O(n log n) and give a reasonable tour even with
+// 100k+ cities; an empty name means a random permutation.
+func InitialTour(coords [][2]float64, name string) []int {
+	switch name {
+	case InitialHilbert:
+		return HilbertOrder(coords)
+	case InitialSierpinski:
+		return SierpinskiOrder(coords)
+	default:
+		return rand.Perm(len(coords))
+	}
+}
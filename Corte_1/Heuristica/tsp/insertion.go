@@ -1,95 +1,269 @@
 package tsp
 
-import "math"
+import (
+	"math"
+	"math/rand"
+)
 
 // FarthestInsertion constructs a tour using the farthest insertion heuristic
 func FarthestInsertion(inst *Instance) ([]int, float64) {
-	n := inst.NumCities
-	if n < 3 {
+	if inst.NumCities < 3 {
 		return nil, 0
 	}
+	tour := FarthestInsertionFunc(inst.NumCities, inst.Dist)
+	return tour, inst.TourLength(tour)
+}
+
+// FarthestInsertionFunc is the shared farthest insertion used by every
+// solver in the repo; dist returns the distance between cities i and j.
+//
+// It runs in O(n^2) time and O(n) memory: minDist keeps each outside city's
+// distance to the tour and is updated only against the newly inserted city,
+// and the tour is a circular linked list so insertion is O(1). Tie-breaking
+// matches the original O(n^3) version, so the tours are identical.
+func FarthestInsertionFunc(n int, dist func(i, j int) float64) []int {
+	if n < 3 {
+		perm := make([]int, n)
+		for i := range perm {
+			perm[i] = i
+		}
+		return perm
+	}
 
 	// Start with the two farthest cities
 	maxDist := -1.0
 	var city1, city2 int
 	for i := 0; i < n; i++ {
 		for j := i + 1; j < n; j++ {
-			if inst.Distance[i][j] > maxDist {
-				maxDist = inst.Distance[i][j]
+			if d := dist(i, j); d > maxDist {
+				maxDist = d
 				city1, city2 = i, j
 			}
 		}
 	}
 
-	// Find the city farthest from these two
-	maxMinDist := -1.0
-	city3 := -1
-	for i := 0; i < n; i++ {
-		if i == city1 || i == city2 {
-			continue
-		}
-		minDist := math.Min(inst.Distance[city1][i], inst.Distance[city2][i])
-		if minDist > maxMinDist {
-			maxMinDist = minDist
-			city3 = i
-		}
+	// next[c] is the successor of c in the partial tour (-1 if c is outside)
+	next := make([]int, n)
+	for i := range next {
+		next[i] = -1
 	}
+	minDist := make([]float64, n)
+	for c := 0; c < n; c++ {
+		minDist[c] = min(dist(city1, c), dist(city2, c))
+	}
+	next[city1], next[city2] = city2, city1
+	size := 2
 
-	// Initialize tour
-	tour := []int{city1, city2, city3}
-	inTour := make([]bool, n)
-	inTour[city1] = true
-	inTour[city2] = true
-	inTour[city3] = true
-
-	// Insert remaining cities one by one
-	for len(tour) < n {
+	for size < n {
 		// Find the city farthest from the tour
 		farthestCity := -1
 		maxMinDist := -1.0
-
 		for c := 0; c < n; c++ {
-			if inTour[c] {
-				continue
+			if next[c] < 0 && minDist[c] > maxMinDist {
+				maxMinDist = minDist[c]
+				farthestCity = c
 			}
+		}
 
-			// Find minimum distance from c to any city in tour
-			minDist := math.MaxFloat64
-			for _, t := range tour {
-				if inst.Distance[c][t] < minDist {
-					minDist = inst.Distance[c][t]
+		// Find the best edge to insert it into, walking from city1 like the
+		// array version walked from tour[0]. The third city (size == 2) goes
+		// after city2, as in the original initial tour {city1, city2, city3}.
+		bestAfter := city2
+		if size > 2 {
+			bestCost := math.MaxFloat64
+			i := city1
+			for k := 0; k < size; k++ {
+				j := next[i]
+				costIncrease := dist(i, farthestCity) + dist(farthestCity, j) - dist(i, j)
+				if costIncrease < bestCost {
+					bestCost = costIncrease
+					bestAfter = i
 				}
+				i = j
 			}
+		}
 
-			if minDist > maxMinDist {
-				maxMinDist = minDist
-				farthestCity = c
+		// Insert the city and update the distances to the tour
+		next[farthestCity] = next[bestAfter]
+		next[bestAfter] = farthestCity
+		size++
+		for c := 0; c < n; c++ {
+			if next[c] < 0 {
+				if d := dist(c, farthestCity); d < minDist[c] {
+					minDist[c] = d
+				}
+			}
+		}
+	}
+
+	tour := make([]int, 0, n)
+	for c, k := city1, 0; k < n; c, k = next[c], k+1 {
+		tour = append(tour, c)
+	}
+	return tour
+}
+
+// NearestInsertion constructs a tour by repeatedly inserting the outside city
+// closest to the tour at its cheapest position
+func NearestInsertion(inst *Instance) ([]int, float64) {
+	tour := NearestInsertionFunc(inst.NumCities, inst.Dist)
+	return tour, inst.TourLength(tour)
+}
+
+// RandomInsertion constructs a tour by inserting the cities in random order,
+// each at its cheapest position
+func RandomInsertion(inst *Instance) ([]int, float64) {
+	tour := RandomInsertionFunc(inst.NumCities, inst.Dist)
+	return tour, inst.TourLength(tour)
+}
+
+// CheapestInsertion constructs a tour by repeatedly choosing the city and
+// edge with the smallest cost increase, starting from city 0
+func CheapestInsertion(inst *Instance) ([]int, float64) {
+	tour := CheapestInsertionFunc(inst.NumCities, inst.Dist, []int{0})
+	return tour, inst.TourLength(tour)
+}
+
+// NearestInsertionFunc grows the tour from city 0. Like FarthestInsertionFunc
+// it keeps each outside city's distance to the tour, so it runs in O(n^2).
+func NearestInsertionFunc(n int, dist func(i, j int) float64) []int {
+	if n == 0 {
+		return nil
+	}
+	t := newPartialTour(n, []int{0})
+	minDist := make([]float64, n)
+	for c := 0; c < n; c++ {
+		minDist[c] = dist(0, c)
+	}
+	for t.size < n {
+		nearestCity := -1
+		for c := 0; c < n; c++ {
+			if !t.contains(c) && (nearestCity < 0 || minDist[c] < minDist[nearestCity]) {
+				nearestCity = c
 			}
 		}
+		after, _ := t.cheapestEdge(nearestCity, dist)
+		t.insertAfter(after, nearestCity)
+		for c := 0; c < n; c++ {
+			if !t.contains(c) {
+				minDist[c] = min(minDist[c], dist(c, nearestCity))
+			}
+		}
+	}
+	return t.cities()
+}
 
-		// Find the best position to insert this city
-		bestPos := -1
-		bestCost := math.MaxFloat64
+// RandomInsertionFunc inserts the cities in the order of a random
+// permutation, starting from its first city. O(n^2).
+func RandomInsertionFunc(n int, dist func(i, j int) float64) []int {
+	if n == 0 {
+		return nil
+	}
+	order := rand.Perm(n)
+	t := newPartialTour(n, order[:1])
+	for _, c := range order[1:] {
+		after, _ := t.cheapestEdge(c, dist)
+		t.insertAfter(after, c)
+	}
+	return t.cities()
+}
 
-		for pos := 0; pos < len(tour); pos++ {
-			i := tour[pos]
-			j := tour[(pos+1)%len(tour)]
-			costIncrease := inst.Distance[i][farthestCity] + inst.Distance[farthestCity][j] - inst.Distance[i][j]
+// CheapestInsertionFunc completes the partial tour start (in that cyclic
+// order) by cheapest insertion. Each outside city remembers its best edge;
+// after an insertion only the cities whose best edge was the one just split
+// need a full rescan, the rest only compare against the two new edges.
+func CheapestInsertionFunc(n int, dist func(i, j int) float64, start []int) []int {
+	if n == 0 || len(start) == 0 {
+		return nil
+	}
+	t := newPartialTour(n, start)
+	bestAfter := make([]int, n)
+	bestCost := make([]float64, n)
+	for c := 0; c < n; c++ {
+		if !t.contains(c) {
+			bestAfter[c], bestCost[c] = t.cheapestEdge(c, dist)
+		}
+	}
 
-			if costIncrease < bestCost {
-				bestCost = costIncrease
-				bestPos = pos
+	for t.size < n {
+		city := -1
+		for c := 0; c < n; c++ {
+			if !t.contains(c) && (city < 0 || bestCost[c] < bestCost[city]) {
+				city = c
+			}
+		}
+		a := bestAfter[city]
+		b := t.next[a]
+		t.insertAfter(a, city)
+
+		for c := 0; c < n; c++ {
+			if t.contains(c) {
+				continue
+			}
+			if bestAfter[c] == a {
+				bestAfter[c], bestCost[c] = t.cheapestEdge(c, dist)
+				continue
+			}
+			if cost := dist(a, c) + dist(c, city) - dist(a, city); cost < bestCost[c] {
+				bestAfter[c], bestCost[c] = a, cost
+			}
+			if cost := dist(city, c) + dist(c, b) - dist(city, b); cost < bestCost[c] {
+				bestAfter[c], bestCost[c] = city, cost
 			}
 		}
+	}
+	return t.cities()
+}
 
-		// Insert the city
-		newTour := make([]int, len(tour)+1)
-		copy(newTour, tour[:bestPos+1])
-		newTour[bestPos+1] = farthestCity
-		copy(newTour[bestPos+2:], tour[bestPos+1:])
-		tour = newTour
-		inTour[farthestCity] = true
+// partialTour is a circular linked list with the cities inserted so far
+type partialTour struct {
+	next  []int // next[c] is the successor of c (-1 if c is outside)
+	first int
+	size  int
+}
+
+// newPartialTour builds the cycle start[0] -> start[1] -> ... -> start[0]
+func newPartialTour(n int, start []int) *partialTour {
+	t := &partialTour{next: make([]int, n), first: start[0], size: len(start)}
+	for i := range t.next {
+		t.next[i] = -1
+	}
+	for i, c := range start {
+		t.next[c] = start[(i+1)%len(start)]
 	}
+	return t
+}
 
-	return tour, inst.TourLength(tour)
+func (t *partialTour) contains(c int) bool {
+	return t.next[c] >= 0
+}
+
+func (t *partialTour) insertAfter(i, c int) {
+	t.next[c] = t.next[i]
+	t.next[i] = c
+	t.size++
+}
+
+// cheapestEdge returns the city after which c is cheapest to insert and the
+// cost increase of doing so
+func (t *partialTour) cheapestEdge(c int, dist func(i, j int) float64) (int, float64) {
+	bestAfter, bestCost := t.first, math.MaxFloat64
+	i := t.first
+	for k := 0; k < t.size; k++ {
+		j := t.next[i]
+		if cost := dist(i, c) + dist(c, j) - dist(i, j); cost < bestCost {
+			bestAfter, bestCost = i, cost
+		}
+		i = j
+	}
+	return bestAfter, bestCost
+}
+
+// cities returns the tour as a permutation starting at first
+func (t *partialTour) cities() []int {
+	tour := make([]int, 0, t.size)
+	for c, k := t.first, 0; k < t.size; c, k = t.next[c], k+1 {
+		tour = append(tour, c)
+	}
+	return tour
 }
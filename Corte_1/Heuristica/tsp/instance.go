@@ -3,7 +3,8 @@ package tsp
 // Instance represents a TSP problem instance
 type Instance struct {
 	NumCities   int
-	Distance    [][]float64
+	Coords      [][2]float64
+	Distance    [][]float64 // nil above MaxMatrixCities; see Dist
 	OptimalCost float64
 }
 
@@ -14,7 +15,16 @@ func (inst *Instance) TourLength(tour []int) float64 {
 	for i := 0; i < n; i++ {
 		from := tour[i]
 		to := tour[(i+1)%n]
-		total += inst.Distance[from][to]
+		total += inst.Dist(from, to)
 	}
 	return total
 }
+
+// Dist returns the distance between cities i and j, from the matrix if it
+// was built or else from the coordinates (EUC_2D, rounded)
+func (inst *Instance) Dist(i, j int) float64 {
+	if inst.Distance != nil {
+		return inst.Distance[i][j]
+	}
+	return euc2D(inst.Coords[i], inst.Coords[j])
+}
@@ -0,0 +1,53 @@
+package tsp
+
+import "math"
+
+// NearestNeighbor constructs a tour starting at city start by always moving
+// to the closest unvisited city
+func NearestNeighbor(inst *Instance, start int) ([]int, float64) {
+	tour := NearestNeighborFunc(inst.NumCities, inst.Dist, start)
+	return tour, inst.TourLength(tour)
+}
+
+// NearestNeighborAllStarts runs NearestNeighbor from every city and keeps the
+// shortest tour. O(n^3).
+func NearestNeighborAllStarts(inst *Instance) ([]int, float64) {
+	var bestTour []int
+	bestLength := math.MaxFloat64
+	for start := 0; start < inst.NumCities; start++ {
+		tour, length := NearestNeighbor(inst, start)
+		if length < bestLength {
+			bestTour, bestLength = tour, length
+		}
+	}
+	return bestTour, bestLength
+}
+
+// NearestNeighborFunc is the O(n^2) nearest neighbor tour from start
+func NearestNeighborFunc(n int, dist func(i, j int) float64, start int) []int {
+	if n == 0 {
+		return nil
+	}
+	visited := make([]bool, n)
+	tour := make([]int, 0, n)
+	current := start
+	visited[current] = true
+	tour = append(tour, current)
+
+	for len(tour) < n {
+		nearest := -1
+		nearestDist := math.MaxFloat64
+		for c := 0; c < n; c++ {
+			if !visited[c] {
+				if d := dist(current, c); d < nearestDist {
+					nearestDist = d
+					nearest = c
+				}
+			}
+		}
+		visited[nearest] = true
+		tour = append(tour, nearest)
+		current = nearest
+	}
+	return tour
+}
@@ -0,0 +1,203 @@
+package tsp
+
+import "math"
+
+// SavingsNeighbors is the size of the nearest neighbor lists the savings
+// candidates come from. It is wider than GreedyNeighbors: the largest
+// savings are between cities far from the hub, not necessarily nearest
+// neighbors of each other.
+const SavingsNeighbors = 40
+
+// Savings constructs a tour with the Clarke-Wright savings heuristic, using
+// the city closest to the centroid as the hub
+func Savings(inst *Instance) ([]int, float64) {
+	tour := SavingsFunc(inst.NumCities, inst.Dist, inst.Coords, SavingsNeighbors)
+	return tour, inst.TourLength(tour)
+}
+
+// SavingsFunc is the shared savings construction. Every city starts on its
+// own route hub-i-hub; routes are merged end to end in decreasing order of
+// the saving s(i,j) = d(h,i) + d(h,j) - d(i,j), taking as candidates the
+// pairs from the k nearest neighbor lists (coords may be nil, see
+// GreedyEdgeFunc), or all pairs if k >= n-1. The routes left when the candidates run out are merged
+// over all pairs of their ends, and the single final route is closed
+// through the hub.
+func SavingsFunc(n int, dist func(i, j int) float64, coords [][2]float64, k int) []int {
+	if n < 3 {
+		return identity(n)
+	}
+	hub := Hub(n, dist, coords)
+
+	var pairs [][2]int
+	if k >= n-1 {
+		pairs = allPairs(n)
+	} else {
+		for i, list := range neighborLists(n, dist, coords, k) {
+			for _, j := range list {
+				pairs = append(pairs, [2]int{i, j})
+			}
+		}
+	}
+	s := newSavingsMerger(n, dist, hub, nil)
+	s.merge(pairs)
+
+	pairs = pairs[:0]
+	ends := s.ends()
+	for a, i := range ends {
+		for _, j := range ends[a+1:] {
+			pairs = append(pairs, [2]int{i, j})
+		}
+	}
+	s.merge(pairs)
+
+	routes := s.routes()
+	tour := make([]int, 0, n)
+	tour = append(tour, hub)
+	for _, r := range routes {
+		tour = append(tour, r...)
+	}
+	return tour
+}
+
+// SavingsRoutes runs the savings merge over the given candidate pairs (all
+// pairs if nil) and returns the resulting routes, hub excluded. allow, if
+// not nil, is asked right before joining the routes that end at i and j and
+// the merge happens only if it returns true, so a caller can enforce
+// per-route limits such as vehicle capacity by tracking the merges it
+// allows. With allow nil and all pairs it returns a single route.
+func SavingsRoutes(n int, dist func(i, j int) float64, hub int, pairs [][2]int, allow func(i, j int) bool) [][]int {
+	if pairs == nil {
+		pairs = allPairs(n)
+	}
+	s := newSavingsMerger(n, dist, hub, allow)
+	s.merge(pairs)
+	return s.routes()
+}
+
+func allPairs(n int) [][2]int {
+	pairs := make([][2]int, 0, n*(n-1)/2)
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			pairs = append(pairs, [2]int{i, j})
+		}
+	}
+	return pairs
+}
+
+// Hub returns the city closest to the centroid of coords, or the one with
+// the smallest total distance to the rest if coords is nil
+func Hub(n int, dist func(i, j int) float64, coords [][2]float64) int {
+	hub, best := 0, math.MaxFloat64
+	if len(coords) == n {
+		var cx, cy float64
+		for _, c := range coords {
+			cx += c[0]
+			cy += c[1]
+		}
+		cx, cy = cx/float64(n), cy/float64(n)
+		for i, c := range coords {
+			if d := (c[0]-cx)*(c[0]-cx) + (c[1]-cy)*(c[1]-cy); d < best {
+				hub, best = i, d
+			}
+		}
+		return hub
+	}
+	for i := 0; i < n; i++ {
+		total := 0.0
+		for j := 0; j < n; j++ {
+			total += dist(i, j)
+		}
+		if total < best {
+			hub, best = i, total
+		}
+	}
+	return hub
+}
+
+// savingsMerger keeps the routes as paths over the non-hub cities, like the
+// fragments of GreedyEdgeFunc
+type savingsMerger struct {
+	n, hub int
+	dist   func(i, j int) float64
+	allow  func(i, j int) bool
+	adj    [][2]int // path neighbors (-1 = free slot)
+	degree []int
+	uf     *unionFind
+}
+
+func newSavingsMerger(n int, dist func(i, j int) float64, hub int, allow func(i, j int) bool) *savingsMerger {
+	s := &savingsMerger{n: n, hub: hub, dist: dist, allow: allow,
+		adj: make([][2]int, n), degree: make([]int, n), uf: newUnionFind(n)}
+	for v := range s.adj {
+		s.adj[v] = [2]int{-1, -1}
+	}
+	return s
+}
+
+// merge joins routes end to end through the pairs in decreasing saving
+func (s *savingsMerger) merge(pairs [][2]int) {
+	edges := make([]edge, 0, len(pairs))
+	for _, p := range pairs {
+		i, j := min(p[0], p[1]), max(p[0], p[1])
+		if i == j || i == s.hub || j == s.hub {
+			continue
+		}
+		saving := s.dist(s.hub, i) + s.dist(s.hub, j) - s.dist(i, j)
+		edges = append(edges, edge{i, j, -saving})
+	}
+	sortEdges(edges)
+
+	for e, prev := 0, (edge{-1, -1, 0}); e < len(edges); e++ {
+		ed := edges[e]
+		if ed.i == prev.i && ed.j == prev.j {
+			continue
+		}
+		prev = ed
+		if s.degree[ed.i] == 2 || s.degree[ed.j] == 2 || s.uf.find(ed.i) == s.uf.find(ed.j) {
+			continue
+		}
+		if s.allow != nil && !s.allow(ed.i, ed.j) {
+			continue
+		}
+		s.uf.union(ed.i, ed.j)
+		s.adj[ed.i][s.degree[ed.i]] = ed.j
+		s.adj[ed.j][s.degree[ed.j]] = ed.i
+		s.degree[ed.i]++
+		s.degree[ed.j]++
+	}
+}
+
+// ends returns the cities at the end of a route (both ends of each route,
+// or its only city)
+func (s *savingsMerger) ends() []int {
+	var ends []int
+	for v := 0; v < s.n; v++ {
+		if v != s.hub && s.degree[v] < 2 {
+			ends = append(ends, v)
+		}
+	}
+	return ends
+}
+
+// routes walks every route from one of its ends
+func (s *savingsMerger) routes() [][]int {
+	visited := make([]bool, s.n)
+	var routes [][]int
+	for _, start := range s.ends() {
+		if visited[start] {
+			continue
+		}
+		var route []int
+		for prev, cur := -1, start; cur >= 0; {
+			visited[cur] = true
+			route = append(route, cur)
+			next := s.adj[cur][0]
+			if next == prev {
+				next = s.adj[cur][1]
+			}
+			prev, cur = cur, next
+		}
+		routes = append(routes, route)
+	}
+	return routes
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"tsp-common/utils"
 )
 
 // TSPLIBOptimal contains known optimal solutions for available benchmarks
@@ -86,6 +87,19 @@ var TSPLIBOptimal = map[string]float64{
 	"vm1748":    336556,
 }
 
+// MaxMatrixCities is the largest instance for which LoadTSPLIB builds the
+// full distance matrix (72 MB); beyond it distances are computed on demand,
+// which costs a square root per lookup but no O(n^2) memory or load time.
+// It is the same threshold the tsp-common distance matrix uses.
+const MaxMatrixCities = utils.MaxCiudadesMatriz
+
+// euc2D is the TSPLIB EUC_2D distance: Euclidean, rounded to an integer
+func euc2D(a, b [2]float64) float64 {
+	dx := a[0] - b[0]
+	dy := a[1] - b[1]
+	return math.Round(math.Sqrt(dx*dx + dy*dy))
+}
+
 // LoadTSPLIB loads a TSP instance from a TSPLIB format file
 func LoadTSPLIB(filepath string) (*Instance, error) {
 	file, err := os.Open(filepath)
@@ -148,20 +162,21 @@ func LoadTSPLIB(filepath string) (*Instance, error) {
 			coordSlice[i] = coord
 		}
 	}
+	inst.Coords = coordSlice
 
-	// Calculate distance matrix (EUC_2D)
-	inst.Distance = make([][]float64, dimension)
-	for i := range inst.Distance {
-		inst.Distance[i] = make([]float64, dimension)
-	}
+	// Calculate distance matrix (EUC_2D), unless it would not fit in memory
+	if dimension <= MaxMatrixCities {
+		inst.Distance = make([][]float64, dimension)
+		for i := range inst.Distance {
+			inst.Distance[i] = make([]float64, dimension)
+		}
 
-	for i := 0; i < dimension; i++ {
-		for j := i + 1; j < dimension; j++ {
-			dx := coordSlice[i][0] - coordSlice[j][0]
-			dy := coordSlice[i][1] - coordSlice[j][1]
-			d := math.Round(math.Sqrt(dx*dx + dy*dy))
-			inst.Distance[i][j] = d
-			inst.Distance[j][i] = d
+		for i := 0; i < dimension; i++ {
+			for j := i + 1; j < dimension; j++ {
+				d := euc2D(coordSlice[i], coordSlice[j])
+				inst.Distance[i][j] = d
+				inst.Distance[j][i] = d
+			}
 		}
 	}
 
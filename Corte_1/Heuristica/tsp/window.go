@@ -0,0 +1,146 @@
+package tsp
+
+import (
+	"fmt"
+	"math"
+)
+
+// MaxWindow bounds the window size: the DP needs O(2^(k-2) * (k-2)) memory and
+// O(2^(k-2) * (k-2)^2) time per window.
+const MaxWindow = 16
+
+// ValidateWindow returns an error unless k is 0 (disabled) or between 4 and MaxWindow.
+func ValidateWindow(k int) error {
+	if k != 0 && (k < 4 || k > MaxWindow) {
+		return fmt.Errorf("invalid window size %d (0 to disable, or between 4 and %d)", k, MaxWindow)
+	}
+	return nil
+}
+
+// WindowReoptimize is an exact post-processing step: it slides a window of k
+// consecutive cities along the tour and solves each one with Held-Karp
+// dynamic programming keeping its endpoints fixed, accepting any improvement.
+// Passes repeat until no window improves. k around 8-12 is a good trade-off.
+// Returns a copy of the tour and its length.
+func WindowReoptimize(inst *Instance, tour []int, k int) ([]int, float64) {
+	best := WindowReoptimizeFunc(tour, inst.Dist, k)
+	return best, inst.TourLength(best)
+}
+
+// WindowReoptimizeFunc is the shared window reoptimization over a tour of
+// city indices, with distances from dist; it returns an improved copy.
+func WindowReoptimizeFunc(tour []int, dist func(i, j int) float64, k int) []int {
+	best := append([]int(nil), tour...)
+	n := len(best)
+	if k > n {
+		k = n
+	}
+	if k < 4 {
+		return best
+	}
+
+	m := k - 2
+	cost := make([]float64, (1<<m)*m)
+	prev := make([]int8, (1<<m)*m)
+	d := make([]float64, k*k)
+	w := make([]int, k)
+
+	for improved := true; improved; {
+		improved = false
+		for s := 0; s < n; s++ {
+			for t := range w {
+				w[t] = best[(s+t)%n]
+			}
+			for a := 0; a < k; a++ {
+				for b := 0; b < k; b++ {
+					d[a*k+b] = dist(w[a], w[b])
+				}
+			}
+			if solveWindow(w, d, cost, prev) {
+				for t := range w {
+					best[(s+t)%n] = w[t]
+				}
+				improved = true
+			}
+		}
+	}
+	return best
+}
+
+// WindowReoptimizeSlice is WindowReoptimizeFunc for tours stored as slices of
+// cities (of any type), with dist measuring two cities directly.
+func WindowReoptimizeSlice[C any](tour []C, dist func(a, b C) float64, k int) []C {
+	order := make([]int, len(tour))
+	for i := range order {
+		order[i] = i
+	}
+	order = WindowReoptimizeFunc(order, func(i, j int) float64 {
+		return dist(tour[i], tour[j])
+	}, k)
+
+	best := make([]C, len(order))
+	for i, idx := range order {
+		best[i] = tour[idx]
+	}
+	return best
+}
+
+// solveWindow reorders the interior of w to minimize the path from w[0] to
+// w[k-1]. d is the k x k distance matrix between window positions. Reports
+// whether the path improved.
+func solveWindow(w []int, d []float64, cost []float64, prev []int8) bool {
+	k := len(w)
+	m := k - 2
+	current := 0.0
+	for t := 0; t+1 < k; t++ {
+		current += d[t*k+t+1]
+	}
+
+	// cost[mask*m+j]: shortest path from w[0] through the interior cities in
+	// mask ending at interior city j (window position j+1)
+	full := 1<<m - 1
+	for mask := 1; mask <= full; mask++ {
+		for j := 0; j < m; j++ {
+			if mask&(1<<j) == 0 {
+				continue
+			}
+			rest := mask ^ (1 << j)
+			if rest == 0 {
+				cost[mask*m+j] = d[j+1]
+				prev[mask*m+j] = -1
+				continue
+			}
+			best, p := math.Inf(1), int8(-1)
+			for i := 0; i < m; i++ {
+				if rest&(1<<i) == 0 {
+					continue
+				}
+				if c := cost[rest*m+i] + d[(i+1)*k+j+1]; c < best {
+					best, p = c, int8(i)
+				}
+			}
+			cost[mask*m+j], prev[mask*m+j] = best, p
+		}
+	}
+
+	best, last := math.Inf(1), -1
+	for j := 0; j < m; j++ {
+		if c := cost[full*m+j] + d[(j+1)*k+k-1]; c < best {
+			best, last = c, j
+		}
+	}
+	if current-best <= 0.0001 {
+		return false
+	}
+
+	// Rebuild the order backwards
+	interior := make([]int, m)
+	for pos, mask := m-1, full; pos >= 0; pos-- {
+		interior[pos] = w[last+1]
+		p := prev[mask*m+last]
+		mask ^= 1 << last
+		last = int(p)
+	}
+	copy(w[1:k-1], interior)
+	return true
+}
@@ -0,0 +1,620 @@
+package main
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"solucion_exacta/tsp"
+)
+
+// Parámetros del método de planos de corte
+const (
+	VecinosNucleo    = 10  // aristas a los vecinos más cercanos de cada ciudad en el LP inicial
+	MaxCortesRonda   = 100 // cortes agregados por ronda de separación
+	MaxColumnasRonda = 200 // aristas agregadas por ronda de pricing
+	MaxRondasNodo    = 300 // rondas de corte por nodo antes de ramificar
+	pivotesPorLP     = 200000
+)
+
+// corteSEC es una restricción de eliminación de subtours
+// sum(x_e, e en δ(S)) >= 2, guardada por su conjunto S
+type corteSEC struct {
+	S    []bool
+	fila int
+}
+
+// nodoCorte es un nodo del árbol: las aristas fijadas a 0 o 1 y la cota LP
+// del padre
+type nodoCorte struct {
+	fijas map[int]float64
+	cota  float64
+	index int
+}
+
+// colaNodos es un min-heap de nodos por cota (heap.Interface)
+type colaNodos []*nodoCorte
+
+func (c colaNodos) Len() int            { return len(c) }
+func (c colaNodos) Less(i, j int) bool  { return c[i].cota < c[j].cota }
+func (c colaNodos) Swap(i, j int)       { c[i], c[j] = c[j], c[i] }
+func (c *colaNodos) Push(x interface{}) { *c = append(*c, x.(*nodoCorte)) }
+func (c *colaNodos) Pop() interface{} {
+	old := *c
+	n := old[len(old)-1]
+	*c = old[:len(old)-1]
+	return n
+}
+
+// planosCorte es el estado del solver DFJ: el LP con las aristas y cortes
+// agregados hasta ahora, compartido por todos los nodos del árbol
+type planosCorte struct {
+	n         int
+	distances [][]float64
+	enteras   bool
+	p         *lp
+
+	aristas [][2]int       // columna j -> (u, v)
+	indice  map[[2]int]int // (u, v) con u < v -> columna
+	cortes  []corteSEC
+}
+
+// TSPPlanosCorte resuelve el TSP con la formulación de Dantzig-Fulkerson-
+// Johnson por ramificación y corte.
+//
+// El LP empieza con las restricciones de grado y un núcleo de aristas (los
+// VecinosNucleo vecinos más cercanos de cada ciudad y las del tour
+// heurístico); tras cada resolución se agregan las aristas de costo reducido
+// negativo (pricing), así que la cota es la del LP sobre todas las aristas.
+// Los cortes de subtour se separan de forma exacta con cortes mínimos
+// (componentes conexas y Stoer-Wagner). Si la solución sigue fraccionaria se
+// ramifica sobre la arista más cercana a 0.5: el hijo que la fija en 1 se
+// explora enseguida (inmersión, para encontrar pronto buenos tours) y el
+// otro queda en la cola, de la que se toma siempre el nodo de menor cota
+// cuando la inmersión termina. Solo se usan TimeLimit y NodeLimit de config; al cortarse por
+// límite se informa la menor cota LP entre los nodos abiertos.
+func TSPPlanosCorte(distances [][]float64, config BBConfig) BBResult {
+	n := len(distances)
+	lim := limite{inicio: time.Now(), tiempo: config.TimeLimit, nodos: int64(config.NodeLimit)}
+	enteras := distanciasEnteras(distances)
+
+	bestPath, bestCost := cotaSuperiorInicial(distances)
+	fmt.Printf("Cota superior inicial (Farthest Insertion + 2-opt): %.2f\n", bestCost)
+	if n < 4 {
+		return resumenBusqueda(bestPath, bestCost, bestCost, bestCost, true, enteras, 0, 0)
+	}
+
+	pc := nuevoPlanosCorte(distances, enteras, bestPath)
+
+	nodos := &colaNodos{}
+	siguiente := &nodoCorte{fijas: map[int]float64{}, cota: math.Inf(-1)} // nodo de la inmersión en curso
+	cotaAbierta := func() float64 {
+		c := bestCost
+		if nodos.Len() > 0 {
+			c = min(c, (*nodos)[0].cota)
+		}
+		if siguiente != nil {
+			c = min(c, siguiente.cota)
+		}
+		return c
+	}
+	explorados, podados := 0, 0
+	cotaRaiz := math.Inf(-1)
+	completo := true
+	ultimoResumen := time.Now()
+
+	fmt.Println("Iniciando busqueda")
+	for nodos.Len() > 0 || siguiente != nil {
+		if lim.alcanzado(int64(explorados)) {
+			completo = false
+			break
+		}
+		if time.Since(ultimoResumen) >= ResumenCada {
+			imprimirResumen(time.Since(lim.inicio), int64(explorados), nodos.Len(), bestCost, cotaAbierta())
+			ultimoResumen = time.Now()
+		}
+
+		nodo := siguiente
+		if nodo != nil {
+			siguiente = nil
+		} else {
+			nodo = heap.Pop(nodos).(*nodoCorte)
+		}
+		if podable(nodo.cota, bestCost, enteras) {
+			podados++
+			continue
+		}
+		explorados++
+
+		estado, valor, err := pc.resolverNodo(nodo.fijas, bestCost, lim, explorados == 1)
+		if err != nil {
+			fmt.Printf("Error en el LP: %v\n", err)
+			siguiente = nodo
+			completo = false
+			break
+		}
+		if estado == lpLimite {
+			// Cortado a mitad del nodo: sigue abierto con la mejor cota válida
+			siguiente = &nodoCorte{fijas: nodo.fijas, cota: max(nodo.cota, valor)}
+			completo = false
+			break
+		}
+		if explorados == 1 && estado == lpOptimo {
+			cotaRaiz = valor
+		}
+		if estado == lpInfactible || podable(valor, bestCost, enteras) {
+			podados++
+			continue
+		}
+
+		// Solución entera: por la separación exacta es un único ciclo
+		j := pc.aristaFraccionaria()
+		if j < 0 {
+			tour := pc.tour()
+			if tour == nil {
+				continue
+			}
+			costo := (&tsp.Instance{NumCities: n, Distance: distances}).TourLength(tour)
+			if costo < bestCost {
+				bestPath, bestCost = tour, costo
+				fmt.Printf("Nueva mejor solución costo: %.2f | Nodos: %d\n", bestCost, explorados)
+			}
+			continue
+		}
+
+		for _, v := range []float64{1, 0} {
+			fijas := make(map[int]float64, len(nodo.fijas)+1)
+			for k, f := range nodo.fijas {
+				fijas[k] = f
+			}
+			fijas[j] = v
+			if v == 1 {
+				siguiente = &nodoCorte{fijas: fijas, cota: valor}
+			} else {
+				heap.Push(nodos, &nodoCorte{fijas: fijas, cota: valor})
+			}
+		}
+		pc.purgarCortes()
+	}
+
+	cota := bestCost
+	if !completo {
+		cota = cotaAbierta()
+	}
+	if math.IsInf(cotaRaiz, -1) {
+		cotaRaiz = cota
+	}
+	fmt.Printf("LP: %d filas (%d cortes), %d columnas\n", pc.p.m, len(pc.cortes), len(pc.aristas))
+	return resumenBusqueda(bestPath, bestCost, cota, cotaRaiz, completo, enteras, explorados, podados)
+}
+
+// nuevoPlanosCorte arma el LP inicial: restricciones de grado y el núcleo de
+// aristas, que incluye las del tour heurístico para que sea factible
+func nuevoPlanosCorte(distances [][]float64, enteras bool, tour []int) *planosCorte {
+	n := len(distances)
+	pc := &planosCorte{n: n, distances: distances, enteras: enteras, p: &lp{}, indice: map[[2]int]int{}}
+	for v := 0; v < n; v++ {
+		pc.p.agregarFila(nil, 2, 0, 0)
+	}
+
+	var nucleo [][2]int
+	vistas := map[[2]int]bool{}
+	agregar := func(u, v int) {
+		e := [2]int{min(u, v), max(u, v)}
+		if u != v && !vistas[e] {
+			vistas[e] = true
+			nucleo = append(nucleo, e)
+		}
+	}
+	for i := range tour {
+		agregar(tour[i], tour[(i+1)%n])
+	}
+	orden := make([]int, n)
+	for u := 0; u < n; u++ {
+		for i := range orden {
+			orden[i] = i
+		}
+		sort.Slice(orden, func(a, b int) bool { return distances[u][orden[a]] < distances[u][orden[b]] })
+		for _, v := range orden[1:min(n, VecinosNucleo+1)] {
+			agregar(u, v)
+		}
+	}
+	pc.agregarAristas(nucleo)
+	return pc
+}
+
+// agregarAristas agrega columnas al LP con sus filas de grado y de los
+// cortes que las cruzan
+func (pc *planosCorte) agregarAristas(aristas [][2]int) {
+	costos := make([]float64, len(aristas))
+	filas := make([][]int, len(aristas))
+	for i, e := range aristas {
+		costos[i] = pc.distances[e[0]][e[1]]
+		filas[i] = []int{e[0], e[1]}
+		for _, c := range pc.cortes {
+			if c.S[e[0]] != c.S[e[1]] {
+				filas[i] = append(filas[i], c.fila)
+			}
+		}
+	}
+	for i, j := range pc.p.agregarColumnas(costos, filas) {
+		pc.aristas = append(pc.aristas, aristas[i])
+		pc.indice[aristas[i]] = j
+	}
+}
+
+// resolverNodo resuelve el LP del nodo con las aristas fijas dadas,
+// alternando simplex dual, pricing y separación. Devuelve el estado y el
+// valor del LP, que es una cota válida del nodo salvo con lpLimite (en ese
+// caso es la última cota válida obtenida, o -Inf).
+func (pc *planosCorte) resolverNodo(fijas map[int]float64, incumbente float64, lim limite, raiz bool) (int, float64, error) {
+	for j := range pc.aristas {
+		if f, ok := fijas[j]; ok {
+			pc.p.fijarCotas(j, f, f)
+		} else {
+			pc.p.fijarCotas(j, 0, 1)
+		}
+	}
+	pc.p.recalcularBasicas()
+
+	valido := math.Inf(-1)
+	previo, estancadas := math.Inf(-1), 0
+	for ronda := 1; ; ronda++ {
+		if lim.alcanzado(0) {
+			return lpLimite, valido, nil
+		}
+		estado, err := pc.p.resolver(pivotesPorLP)
+		if err != nil {
+			return 0, 0, err
+		}
+		if estado == lpInfactible && pc.farkas() {
+			continue
+		}
+		if estado != lpOptimo {
+			return estado, valido, nil
+		}
+		if pc.pricing() {
+			continue
+		}
+
+		// Sin aristas de costo reducido negativo: el valor es una cota
+		valor := pc.p.objetivo()
+		valido = valor
+		if podable(valor, incumbente, pc.enteras) {
+			return lpOptimo, valor, nil
+		}
+
+		cortes := pc.separar()
+		if raiz {
+			fmt.Printf("Raiz: ronda %d, LP %.2f, cortes nuevos %d, filas %d, columnas %d\n",
+				ronda, valor, len(cortes), pc.p.m, len(pc.aristas))
+		}
+		if len(cortes) == 0 {
+			return lpOptimo, valor, nil
+		}
+		fraccionaria := pc.aristaFraccionaria() >= 0
+		if ronda >= MaxRondasNodo && fraccionaria {
+			return lpOptimo, valor, nil
+		}
+
+		// Cola: si la cota casi no mejora en varias rondas conviene ramificar
+		// (salvo con solución entera, que no tendría arista para ramificar)
+		if valor-previo < 1e-6*math.Abs(valor) && fraccionaria {
+			if estancadas++; estancadas >= 10 {
+				return lpOptimo, valor, nil
+			}
+		} else {
+			estancadas = 0
+		}
+		previo = valor
+
+		for _, S := range cortes {
+			var cols []int
+			for j, e := range pc.aristas {
+				if S[e[0]] != S[e[1]] {
+					cols = append(cols, j)
+				}
+			}
+			fila := pc.p.agregarFila(cols, 2, math.Inf(-1), 0)
+			pc.cortes = append(pc.cortes, corteSEC{S: S, fila: fila})
+		}
+	}
+}
+
+// farkas agrega las aristas fuera del LP que podrían hacer factible el LP
+// del nodo (por ejemplo, cuando las aristas fijadas a 0 dejan a una ciudad
+// sin aristas en el núcleo). Devuelve false si no hay ninguna, es decir, si
+// el nodo es infactible de verdad.
+func (pc *planosCorte) farkas() bool {
+	y := pc.p.duales()
+	rayo := pc.p.rayo
+	type cand struct {
+		e [2]int
+		a float64
+	}
+	var cands []cand
+	for u := 0; u < pc.n; u++ {
+		for v := u + 1; v < pc.n; v++ {
+			if _, ok := pc.indice[[2]int{u, v}]; ok {
+				continue
+			}
+			a := rayo[u] + rayo[v]
+			d := pc.distances[u][v] - y[u] - y[v]
+			for _, c := range pc.cortes {
+				if c.S[u] != c.S[v] {
+					a += rayo[c.fila]
+					d -= y[c.fila]
+				}
+			}
+			// La columna entra en 0 si d >= 0 (solo puede subir) o en 1 si
+			// d < 0 (solo puede bajar); ver el test de razón de lp.resolver
+			if d < 0 {
+				a = -a
+			}
+			if (pc.p.subirRayo && a < -tolPivote) || (!pc.p.subirRayo && a > tolPivote) {
+				cands = append(cands, cand{[2]int{u, v}, math.Abs(a)})
+			}
+		}
+	}
+	if len(cands) == 0 {
+		return false
+	}
+	sort.Slice(cands, func(a, b int) bool { return cands[a].a > cands[b].a })
+	if len(cands) > MaxColumnasRonda {
+		cands = cands[:MaxColumnasRonda]
+	}
+	aristas := make([][2]int, len(cands))
+	for i, c := range cands {
+		aristas[i] = c.e
+	}
+	pc.agregarAristas(aristas)
+	return true
+}
+
+// pricing agrega las aristas fuera del LP con costo reducido negativo (las
+// MaxColumnasRonda más negativas). Devuelve true si agregó alguna.
+func (pc *planosCorte) pricing() bool {
+	y := pc.p.duales()
+	type cand struct {
+		e [2]int
+		d float64
+	}
+	var cands []cand
+	for u := 0; u < pc.n; u++ {
+		for v := u + 1; v < pc.n; v++ {
+			if _, ok := pc.indice[[2]int{u, v}]; ok {
+				continue
+			}
+			d := pc.distances[u][v] - y[u] - y[v]
+			for _, c := range pc.cortes {
+				if c.S[u] != c.S[v] {
+					d -= y[c.fila]
+				}
+			}
+			if d < -1e-6 {
+				cands = append(cands, cand{[2]int{u, v}, d})
+			}
+		}
+	}
+	if len(cands) == 0 {
+		return false
+	}
+	sort.Slice(cands, func(a, b int) bool { return cands[a].d < cands[b].d })
+	if len(cands) > MaxColumnasRonda {
+		cands = cands[:MaxColumnasRonda]
+	}
+	aristas := make([][2]int, len(cands))
+	for i, c := range cands {
+		aristas[i] = c.e
+	}
+	pc.agregarAristas(aristas)
+	return true
+}
+
+// separar busca cortes de subtour violados en la solución actual: si el
+// soporte es disconexo cada componente es uno, si no se usan los cortes de
+// fase de Stoer-Wagner con valor menor que 2
+func (pc *planosCorte) separar() [][]bool {
+	n := pc.n
+	w := make([][]float64, n)
+	for i := range w {
+		w[i] = make([]float64, n)
+	}
+	for j, e := range pc.aristas {
+		if x := pc.p.x[j]; x > 1e-9 {
+			w[e[0]][e[1]] += x
+			w[e[1]][e[0]] += x
+		}
+	}
+
+	if comps := componentes(w); len(comps) > 1 {
+		return comps
+	}
+	cortes := cortesDeFase(w, 2-1e-6)
+	if len(cortes) > MaxCortesRonda {
+		cortes = cortes[:MaxCortesRonda]
+	}
+	return cortes
+}
+
+// componentes devuelve las componentes conexas del grafo con pesos w > 0
+// (como conjuntos), o una sola si es conexo
+func componentes(w [][]float64) [][]bool {
+	n := len(w)
+	comp := make([]int, n)
+	for i := range comp {
+		comp[i] = -1
+	}
+	var res [][]bool
+	for s := 0; s < n; s++ {
+		if comp[s] >= 0 {
+			continue
+		}
+		S := make([]bool, n)
+		pila := []int{s}
+		comp[s] = len(res)
+		for len(pila) > 0 {
+			u := pila[len(pila)-1]
+			pila = pila[:len(pila)-1]
+			S[u] = true
+			for v := 0; v < n; v++ {
+				if comp[v] < 0 && w[u][v] > 1e-9 {
+					comp[v] = len(res)
+					pila = append(pila, v)
+				}
+			}
+		}
+		res = append(res, S)
+	}
+	return res
+}
+
+// cortesDeFase ejecuta Stoer-Wagner sobre w (que modifica) y devuelve los
+// conjuntos de todos los cortes de fase con valor menor que umbral, sin
+// repetidos y ordenados de menor a mayor valor. El mínimo de ellos es el
+// corte mínimo global, así que si no devuelve nada no hay corte violado.
+func cortesDeFase(w [][]float64, umbral float64) [][]bool {
+	n := len(w)
+	miembros := make([][]int, n)
+	activo := make([]bool, n)
+	for i := range miembros {
+		miembros[i] = []int{i}
+		activo[i] = true
+	}
+	type fase struct {
+		S     []bool
+		valor float64
+	}
+	var fases []fase
+	vistos := map[string]bool{}
+
+	clave := make([]float64, n)
+	enA := make([]bool, n)
+	for quedan := n; quedan > 1; quedan-- {
+		for i := range clave {
+			clave[i], enA[i] = 0, false
+		}
+		prev, ult := -1, -1
+		for paso := 0; paso < quedan; paso++ {
+			sel := -1
+			for v := 0; v < n; v++ {
+				if activo[v] && !enA[v] && (sel < 0 || clave[v] > clave[sel]) {
+					sel = v
+				}
+			}
+			enA[sel] = true
+			prev, ult = ult, sel
+			for v := 0; v < n; v++ {
+				if activo[v] && !enA[v] {
+					clave[v] += w[sel][v]
+				}
+			}
+		}
+
+		if clave[ult] < umbral {
+			S := make([]bool, n)
+			for _, c := range miembros[ult] {
+				S[c] = true
+			}
+			// Forma canónica: el lado que no contiene la ciudad 0
+			if S[0] {
+				for i := range S {
+					S[i] = !S[i]
+				}
+			}
+			k := fmt.Sprint(S)
+			if !vistos[k] {
+				vistos[k] = true
+				fases = append(fases, fase{S, clave[ult]})
+			}
+		}
+
+		// Fusionar ult en prev
+		miembros[prev] = append(miembros[prev], miembros[ult]...)
+		activo[ult] = false
+		for v := 0; v < n; v++ {
+			w[prev][v] += w[ult][v]
+			w[v][prev] = w[prev][v]
+		}
+		w[prev][prev] = 0
+	}
+
+	sort.Slice(fases, func(a, b int) bool { return fases[a].valor < fases[b].valor })
+	res := make([][]bool, len(fases))
+	for i, f := range fases {
+		res[i] = f.S
+	}
+	return res
+}
+
+// aristaFraccionaria devuelve la arista con valor más cercano a 0.5, o -1 si
+// la solución es entera
+func (pc *planosCorte) aristaFraccionaria() int {
+	mejor, dist := -1, 0.5-1e-6
+	for j, x := range pc.p.x {
+		if d := math.Abs(x - 0.5); d < dist {
+			mejor, dist = j, d
+		}
+	}
+	return mejor
+}
+
+// tour arma el ciclo de una solución entera, o nil si no es un único ciclo
+func (pc *planosCorte) tour() []int {
+	ady := make([][]int, pc.n)
+	for j, e := range pc.aristas {
+		if pc.p.x[j] > 0.5 {
+			ady[e[0]] = append(ady[e[0]], e[1])
+			ady[e[1]] = append(ady[e[1]], e[0])
+		}
+	}
+	tour := []int{0}
+	prev, act := -1, 0
+	for len(tour) < pc.n {
+		if len(ady[act]) != 2 {
+			return nil
+		}
+		sig := ady[act][0]
+		if sig == prev {
+			sig = ady[act][1]
+		}
+		if sig == 0 {
+			return nil
+		}
+		tour = append(tour, sig)
+		prev, act = act, sig
+	}
+	return tour
+}
+
+// purgarCortes elimina del LP los cortes inactivos (holgura básica y no
+// saturada) cuando ya hay más cortes que ciudades, para que la base no
+// crezca sin límite; si vuelven a violarse la separación los encuentra
+func (pc *planosCorte) purgarCortes() {
+	if len(pc.cortes) <= pc.n {
+		return
+	}
+	quitar := map[int]bool{}
+	for _, c := range pc.cortes {
+		if pc.p.estadoL[c.fila] == basica && pc.p.xL[c.fila] < -1e-6 {
+			quitar[c.fila] = true
+		}
+	}
+	if len(quitar) == 0 {
+		return
+	}
+	nuevo, err := pc.p.eliminarFilas(quitar)
+	if err != nil {
+		return
+	}
+	quedan := pc.cortes[:0]
+	for _, c := range pc.cortes {
+		if nuevo[c.fila] >= 0 {
+			c.fila = nuevo[c.fila]
+			quedan = append(quedan, c)
+		}
+	}
+	pc.cortes = quedan
+}
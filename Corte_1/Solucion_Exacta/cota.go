@@ -0,0 +1,156 @@
+package main
+
+import "math"
+
+// Iteraciones del subgradiente: muchas en la raíz, donde se calculan las
+// penalizaciones de partida, y pocas en cada hijo, que arranca de las
+// penalizaciones de su padre (warm start).
+const (
+	IteracionesRaiz = 1000
+	IteracionesHijo = 15
+)
+
+// unoArbol evalúa la cota de Held-Karp de un nodo con penalizaciones pi.
+//
+// El nodo es un camino fijo 0 -> ... -> ultimo de costo costo; lo que falta
+// es un camino hamiltoniano ultimo -> (libres) -> 0. Contrayendo el camino
+// fijo en un nodo especial, cualquier forma de completarlo es un 1-árbol:
+// un árbol generador sobre libres más una arista de ultimo hacia libres y
+// otra de libres hacia 0. Con pesos d(i,j) + pi[i] + pi[j] el 1-árbol mínimo
+// menos 2*sum(pi) es una cota inferior para cualquier pi.
+//
+// Devuelve la cota y el grado de cada ciudad de libres en el 1-árbol (nil
+// si libres tiene menos de dos ciudades y la cota es el costo exacto).
+func unoArbol(distances [][]float64, costo float64, ultimo int, libres []int, pi []float64) (float64, []int) {
+	k := len(libres)
+	switch k {
+	case 0:
+		return costo + distances[ultimo][0], nil
+	case 1:
+		u := libres[0]
+		return costo + distances[ultimo][u] + distances[u][0], nil
+	}
+
+	grado := make([]int, k)
+	total := costo
+
+	// Prim O(k^2) sobre libres con los pesos penalizados
+	enArbol := make([]bool, k)
+	clave := make([]float64, k)
+	padre := make([]int, k)
+	for i := range clave {
+		clave[i] = math.Inf(1)
+		padre[i] = -1
+	}
+	clave[0] = 0
+	for paso := 0; paso < k; paso++ {
+		a := -1
+		for i := 0; i < k; i++ {
+			if !enArbol[i] && (a < 0 || clave[i] < clave[a]) {
+				a = i
+			}
+		}
+		enArbol[a] = true
+		if padre[a] >= 0 {
+			total += clave[a]
+			grado[a]++
+			grado[padre[a]]++
+		}
+		ca := libres[a]
+		for b := 0; b < k; b++ {
+			if !enArbol[b] {
+				cb := libres[b]
+				if w := distances[ca][cb] + pi[ca] + pi[cb]; w < clave[b] {
+					clave[b] = w
+					padre[b] = a
+				}
+			}
+		}
+	}
+
+	// Las dos aristas del nodo especial deben llegar a ciudades distintas:
+	// se guardan las dos mejores de cada lado y se combinan
+	salida1, salida2 := mejoresDos(k, func(i int) float64 { return distances[ultimo][libres[i]] + pi[libres[i]] })
+	entrada1, entrada2 := mejoresDos(k, func(i int) float64 { return distances[libres[i]][0] + pi[libres[i]] })
+	s, e := salida1, entrada1
+	if s.i == e.i {
+		if salida2.w+entrada1.w < salida1.w+entrada2.w {
+			s = salida2
+		} else {
+			e = entrada2
+		}
+	}
+	total += s.w + e.w
+	grado[s.i]++
+	grado[e.i]++
+
+	for _, c := range libres {
+		total -= 2 * pi[c]
+	}
+	return total, grado
+}
+
+type aristaEspecial struct {
+	i int
+	w float64
+}
+
+// mejoresDos devuelve los dos índices de menor w en [0, k)
+func mejoresDos(k int, w func(i int) float64) (aristaEspecial, aristaEspecial) {
+	m1 := aristaEspecial{-1, math.Inf(1)}
+	m2 := m1
+	for i := 0; i < k; i++ {
+		if v := w(i); v < m1.w {
+			m1, m2 = aristaEspecial{i, v}, m1
+		} else if v < m2.w {
+			m2 = aristaEspecial{i, v}
+		}
+	}
+	return m1, m2
+}
+
+// Subgradiente maximiza la cota de unoArbol sobre las penalizaciones
+// (optimización de Held-Karp). Parte de pi (que no modifica), da iteraciones
+// pasos de tamaño lambda*(ub-L)/||g||^2 con g = grado-2 y reduce lambda a la
+// mitad cuando la cota deja de mejorar. ub es una cota superior (solo se usa
+// para el tamaño del paso). Devuelve la mejor cota y sus penalizaciones.
+// Si el 1-árbol resulta ser un camino la cota ya es el costo de la mejor
+// forma de completar el nodo y se detiene antes.
+func Subgradiente(distances [][]float64, costo float64, ultimo int, libres []int, pi []float64, iteraciones int, lambda, ub float64) (mejor float64, mejorPi []float64) {
+	actual := append([]float64(nil), pi...)
+	mejorPi = actual
+	mejor = math.Inf(-1)
+	sinMejora := 0
+	paciencia := max(iteraciones/20, 3)
+
+	for it := 0; it < iteraciones; it++ {
+		l, grado := unoArbol(distances, costo, ultimo, libres, actual)
+		if l > mejor {
+			mejor = l
+			mejorPi = append([]float64(nil), actual...)
+			sinMejora = 0
+		} else if sinMejora++; sinMejora >= paciencia {
+			lambda /= 2
+			sinMejora = 0
+		}
+		if grado == nil {
+			break
+		}
+
+		norma := 0
+		for _, g := range grado {
+			norma += (g - 2) * (g - 2)
+		}
+		if norma == 0 {
+			break
+		}
+		if ub <= l || lambda < 1e-6 {
+			break
+		}
+		paso := lambda * (ub - l) / float64(norma)
+		for i, c := range libres {
+			actual[c] += paso * float64(grado[i]-2)
+		}
+	}
+	return mejor, mejorPi
+}
@@ -0,0 +1,347 @@
+package main
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Estrategias de búsqueda de TSPBranchBoundWithLB
+const (
+	ModoMejorPrimero = "best" // Best-First con cola de prioridad
+	ModoProfundidad  = "dfs"  // Depth-First con hijos ordenados por cota
+)
+
+// Modos lista las estrategias válidas
+var Modos = []string{ModoMejorPrimero, ModoProfundidad}
+
+// ValidarModo devuelve un error si modo no es una estrategia conocida
+func ValidarModo(modo string) error {
+	for _, m := range Modos {
+		if m == modo {
+			return nil
+		}
+	}
+	return fmt.Errorf("estrategia de busqueda desconocida %q (opciones: %v)", modo, Modos)
+}
+
+// bitset es un conjunto de ciudades, un bit por ciudad
+type bitset []uint64
+
+func nuevoBitset(n int) bitset    { return make(bitset, (n+63)/64) }
+func (b bitset) tiene(i int) bool { return b[i/64]&(1<<(i%64)) != 0 }
+func (b bitset) poner(i int)      { b[i/64] |= 1 << (i % 64) }
+func (b bitset) quitar(i int)     { b[i/64] &^= 1 << (i % 64) }
+
+// tarea es un subárbol pendiente: el camino fijo desde la ciudad 0, su
+// costo y la cota con la que se obtuvo (y sus penalizaciones)
+type tarea struct {
+	camino []int
+	costo  float64
+	lb     float64
+	pi     []float64
+}
+
+// colaTareas ordena las tareas compartidas por cota inferior (heap.Interface)
+type colaTareas []tarea
+
+func (c colaTareas) Len() int            { return len(c) }
+func (c colaTareas) Less(i, j int) bool  { return c[i].lb < c[j].lb }
+func (c colaTareas) Swap(i, j int)       { c[i], c[j] = c[j], c[i] }
+func (c *colaTareas) Push(x interface{}) { *c = append(*c, x.(tarea)) }
+func (c *colaTareas) Pop() interface{} {
+	old := *c
+	t := old[len(old)-1]
+	*c = old[:len(old)-1]
+	return t
+}
+
+// minDonacion es el mínimo de ciudades por visitar para que un subárbol se
+// comparta; por debajo es más barato resolverlo que pasarlo a otro worker
+const minDonacion = 8
+
+// busquedaProfundidad es el estado compartido de la búsqueda en
+// profundidad con varios workers.
+//
+// Cada worker toma de la cola compartida el subárbol de menor cota y lo
+// recorre en profundidad con su propio camino y bitset de visitadas, que se
+// extienden al bajar y se deshacen al volver (memoria O(n) por nivel en lugar
+// de una copia por nodo abierto). Mientras haya workers ociosos, los que
+// trabajan les donan hijos a la cola en lugar de explorarlos ellos mismos.
+// El costo del incumbente se lee sin bloqueo para podar.
+type busquedaProfundidad struct {
+	distances [][]float64
+	enteras   bool
+
+	incumbente  atomic.Uint64 // bits de math.Float64bits del mejor costo
+	mu          sync.Mutex    // protege mejorCamino y las escrituras a incumbente
+	mejorCamino []int
+
+	colaMu  sync.Mutex
+	hayCola *sync.Cond
+	cola    colaTareas
+	activos int          // workers resolviendo una tarea
+	ociosos atomic.Int32 // workers esperando tarea
+	enCola  atomic.Int32 // len(cola), para leerlo sin bloqueo
+
+	explorados atomic.Int64
+	podados    atomic.Int64
+
+	// Al alcanzar lim se activa detenido y los workers abandonan sus nodos
+	// pendientes; cotaAbandonada (protegida por mu) es la menor de sus cotas
+	lim            limite
+	detenido       atomic.Bool
+	cotaAbandonada float64
+	cotaRaiz       float64
+}
+
+// nuevaBusquedaProfundidad prepara la búsqueda con el incumbente inicial
+func nuevaBusquedaProfundidad(distances [][]float64, enteras bool, mejorCamino []int, mejorCosto float64, lim limite, cotaRaiz float64) *busquedaProfundidad {
+	b := &busquedaProfundidad{
+		distances:      distances,
+		enteras:        enteras,
+		mejorCamino:    mejorCamino,
+		lim:            lim,
+		cotaAbandonada: math.Inf(1),
+		cotaRaiz:       cotaRaiz,
+	}
+	b.hayCola = sync.NewCond(&b.colaMu)
+	b.incumbente.Store(math.Float64bits(mejorCosto))
+	return b
+}
+
+// mejorCosto es el costo del incumbente actual
+func (b *busquedaProfundidad) mejorCosto() float64 {
+	return math.Float64frombits(b.incumbente.Load())
+}
+
+// ejecutar resuelve el árbol que cuelga de raiz con el número de workers
+// dado y devuelve el mejor tour, su costo y la cota inferior global (igual
+// al costo si la búsqueda terminó sin alcanzar el límite)
+func (b *busquedaProfundidad) ejecutar(raiz tarea, workers int) ([]int, float64, float64) {
+	heap.Push(&b.cola, raiz)
+	b.enCola.Store(1)
+
+	// Resumen periódico. Sin recorrer las pilas de los workers no se conoce
+	// la menor cota abierta, así que se muestra la de la raíz (que sigue
+	// siendo una cota válida)
+	listo := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(ResumenCada)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-listo:
+				return
+			case <-ticker.C:
+				imprimirResumen(time.Since(b.lim.inicio), b.explorados.Load(), int(b.enCola.Load()), b.mejorCosto(), b.cotaRaiz)
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			n := len(b.distances)
+			camino := make([]int, 0, n)
+			visitado := nuevoBitset(n)
+			for {
+				t, ok := b.tomar()
+				if !ok {
+					return
+				}
+				camino = append(camino[:0], t.camino...)
+				for i := range visitado {
+					visitado[i] = 0
+				}
+				for _, c := range camino {
+					visitado.poner(c)
+				}
+				b.explorar(camino, visitado, t.costo, t.lb, t.pi)
+				b.terminar()
+			}
+		}()
+	}
+	wg.Wait()
+	close(listo)
+
+	cota := b.mejorCosto()
+	if b.detenido.Load() {
+		cota = min(cota, b.cotaAbandonada)
+		if len(b.cola) > 0 {
+			cota = min(cota, b.cola[0].lb)
+		}
+	}
+	return b.mejorCamino, b.mejorCosto(), cota
+}
+
+// tomar espera una tarea de la cola compartida. Devuelve false cuando la
+// cola está vacía y ningún worker puede generar más, es decir, al terminar,
+// o cuando se alcanzó el límite (las tareas en cola quedan abiertas).
+func (b *busquedaProfundidad) tomar() (tarea, bool) {
+	b.colaMu.Lock()
+	defer b.colaMu.Unlock()
+	for len(b.cola) == 0 && b.activos > 0 && !b.detenido.Load() {
+		b.ociosos.Add(1)
+		b.hayCola.Wait()
+		b.ociosos.Add(-1)
+	}
+	if len(b.cola) == 0 || b.detenido.Load() {
+		b.hayCola.Broadcast()
+		return tarea{}, false
+	}
+	t := heap.Pop(&b.cola).(tarea)
+	b.enCola.Store(int32(len(b.cola)))
+	b.activos++
+	return t, true
+}
+
+// terminar marca como resuelta la tarea del worker
+func (b *busquedaProfundidad) terminar() {
+	b.colaMu.Lock()
+	b.activos--
+	if (b.activos == 0 && len(b.cola) == 0) || b.detenido.Load() {
+		b.hayCola.Broadcast()
+	}
+	b.colaMu.Unlock()
+}
+
+// donar agrega un subárbol a la cola compartida
+func (b *busquedaProfundidad) donar(t tarea) {
+	b.colaMu.Lock()
+	heap.Push(&b.cola, t)
+	b.enCola.Store(int32(len(b.cola)))
+	b.hayCola.Signal()
+	b.colaMu.Unlock()
+}
+
+// hambre indica si hay más workers ociosos que tareas esperando
+func (b *busquedaProfundidad) hambre() bool {
+	return b.ociosos.Load() > b.enCola.Load()
+}
+
+// actualizar registra un tour completo si mejora al incumbente
+func (b *busquedaProfundidad) actualizar(camino []int, costo float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if costo < b.mejorCosto() {
+		b.incumbente.Store(math.Float64bits(costo))
+		b.mejorCamino = append([]int(nil), camino...)
+		fmt.Printf("Nueva mejor solución costo: %.2f | Nodos: %d\n", costo, b.explorados.Load())
+	}
+}
+
+// parar indica si hay que cortar la búsqueda, activando detenido la primera
+// vez que se alcanza el límite
+func (b *busquedaProfundidad) parar() bool {
+	if b.detenido.Load() {
+		return true
+	}
+	if b.lim.alcanzado(b.explorados.Load()) {
+		b.detenido.Store(true)
+		return true
+	}
+	return false
+}
+
+// abandonar registra la cota de un nodo que queda abierto al cortar
+func (b *busquedaProfundidad) abandonar(lb float64) {
+	b.mu.Lock()
+	b.cotaAbandonada = min(b.cotaAbandonada, lb)
+	b.mu.Unlock()
+}
+
+// hijo es un candidato a expandir desde el nodo actual
+type hijo struct {
+	ciudad int
+	costo  float64
+	lb     float64
+	pi     []float64
+}
+
+// explorar expande el nodo cuyo camino es camino (con costo acumulado costo
+// y cota lb obtenida con las penalizaciones pi). Los hijos se visitan en orden
+// creciente de cota, lo que suele encontrar pronto buenos tours y mejorar la
+// poda del resto.
+func (b *busquedaProfundidad) explorar(camino []int, visitado bitset, costo, lb float64, pi []float64) {
+	if b.parar() {
+		b.abandonar(lb)
+		return
+	}
+	b.explorados.Add(1)
+	n := len(b.distances)
+	actual := camino[len(camino)-1]
+
+	if len(camino) == n {
+		if total := costo + b.distances[actual][0]; total < b.mejorCosto() {
+			b.actualizar(camino, total)
+		}
+		return
+	}
+
+	pendientes := make([]int, 0, n-len(camino))
+	for c := 0; c < n; c++ {
+		if !visitado.tiene(c) {
+			pendientes = append(pendientes, c)
+		}
+	}
+
+	hijos := make([]hijo, 0, len(pendientes))
+	libres := make([]int, 0, len(pendientes))
+	for _, c := range pendientes {
+		nuevoCosto := costo + b.distances[actual][c]
+		if nuevoCosto >= b.mejorCosto() {
+			b.podados.Add(1)
+			continue
+		}
+
+		libres = libres[:0]
+		for _, l := range pendientes {
+			if l != c {
+				libres = append(libres, l)
+			}
+		}
+		lb, piHijo := Subgradiente(b.distances, nuevoCosto, c, libres, pi, IteracionesHijo, 0.5, b.mejorCosto())
+		if podable(lb, b.mejorCosto(), b.enteras) {
+			b.podados.Add(1)
+			continue
+		}
+		hijos = append(hijos, hijo{ciudad: c, costo: nuevoCosto, lb: lb, pi: piHijo})
+	}
+
+	sort.Slice(hijos, func(i, j int) bool { return hijos[i].lb < hijos[j].lb })
+
+	for k, h := range hijos {
+		// El incumbente pudo mejorar al explorar los hermanos anteriores
+		if podable(h.lb, b.mejorCosto(), b.enteras) {
+			b.podados.Add(1)
+			continue
+		}
+		// Al cortar, los hermanos restantes quedan abiertos; están ordenados,
+		// así que el actual tiene la menor cota
+		if b.detenido.Load() {
+			b.abandonar(h.lb)
+			return
+		}
+		// El primer hijo se explora siempre aquí; los demás se comparten
+		// si algún worker está esperando
+		if k > 0 && len(pendientes) > minDonacion && b.hambre() {
+			b.donar(tarea{
+				camino: append(append([]int(nil), camino...), h.ciudad),
+				costo:  h.costo,
+				lb:     h.lb,
+				pi:     h.pi,
+			})
+			continue
+		}
+		visitado.poner(h.ciudad)
+		b.explorar(append(camino, h.ciudad), visitado, h.costo, h.lb, h.pi)
+		visitado.quitar(h.ciudad)
+	}
+}
@@ -1,3 +1,11 @@
 module solucion_exacta
 
-go 1.21
+go 1.23
+
+require heuristica v0.0.0
+
+replace heuristica => ../Heuristica
+
+require tsp-common v0.0.0
+
+replace tsp-common => ../../Corte_2/common
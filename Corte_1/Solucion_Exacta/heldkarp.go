@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// MemoriaMaxHeldKarp es la memoria máxima (en bytes) que HeldKarp acepta
+// reservar; con 1 GiB alcanza para 23 ciudades
+const MemoriaMaxHeldKarp = 1 << 30
+
+// MaxCiudadesHeldKarp es la mayor cantidad de ciudades cuya tabla entra en
+// MemoriaMaxHeldKarp; se controla antes de estimar la memoria porque 2^(n-1)
+// desborda un uint64 a partir de 65 ciudades
+var MaxCiudadesHeldKarp = maxCiudadesHeldKarp()
+
+func maxCiudadesHeldKarp() int {
+	n := 1
+	for MemoriaHeldKarp(n+1) <= MemoriaMaxHeldKarp {
+		n++
+	}
+	return n
+}
+
+// MemoriaHeldKarp estima los bytes que necesita HeldKarp para n ciudades:
+// un costo (float64) y un padre (int8) por cada par (subconjunto, última)
+// sobre las n-1 ciudades distintas de la inicial. Solo es válida para
+// n <= MaxCiudadesHeldKarp + 1.
+func MemoriaHeldKarp(n int) uint64 {
+	if n < 2 {
+		return 0
+	}
+	m := uint64(n - 1)
+	return (uint64(1) << m) * m * 9
+}
+
+// HeldKarp resuelve el TSP de forma exacta con programación dinámica sobre
+// subconjuntos en O(2^n n^2) tiempo y O(2^n n) memoria.
+//
+// costo[S][j] es el camino más corto que sale de la ciudad 0, visita
+// exactamente las ciudades de S y termina en j (j en S); padre[S][j] es la
+// ciudad anterior a j en ese camino y sirve para reconstruir el tour. Sirve
+// también para matrices asimétricas. Devuelve error si la memoria estimada
+// supera MemoriaMaxHeldKarp.
+func HeldKarp(distances [][]float64) ([]int, float64, error) {
+	n := len(distances)
+	if n > MaxCiudadesHeldKarp {
+		return nil, 0, fmt.Errorf("Held-Karp admite hasta %d ciudades (maximo %d MiB), la instancia tiene %d",
+			MaxCiudadesHeldKarp, MemoriaMaxHeldKarp>>20, n)
+	}
+	switch n {
+	case 0:
+		return nil, 0, nil
+	case 1:
+		return []int{0}, 0, nil
+	}
+
+	// La ciudad c (1..n-1) es el bit c-1 de la máscara
+	m := n - 1
+	estados := 1 << m
+	costo := make([]float64, estados*m)
+	padre := make([]int8, estados*m)
+	for i := range costo {
+		costo[i] = math.Inf(1)
+	}
+	for j := 0; j < m; j++ {
+		costo[(1<<j)*m+j] = distances[0][j+1]
+		padre[(1<<j)*m+j] = -1
+	}
+
+	for mask := 1; mask < estados; mask++ {
+		fila := costo[mask*m : mask*m+m]
+		for j := 0; j < m; j++ {
+			if mask&(1<<j) == 0 || math.IsInf(fila[j], 1) {
+				continue
+			}
+			dj := distances[j+1]
+			for k := 0; k < m; k++ {
+				if mask&(1<<k) != 0 {
+					continue
+				}
+				siguiente := mask | 1<<k
+				if c := fila[j] + dj[k+1]; c < costo[siguiente*m+k] {
+					costo[siguiente*m+k] = c
+					padre[siguiente*m+k] = int8(j)
+				}
+			}
+		}
+	}
+
+	// Cerrar el ciclo volviendo a la ciudad 0
+	completo := estados - 1
+	mejor, ultimo := math.Inf(1), -1
+	for j := 0; j < m; j++ {
+		if c := costo[completo*m+j] + distances[j+1][0]; c < mejor {
+			mejor, ultimo = c, j
+		}
+	}
+
+	// Reconstruir el tour hacia atrás desde la última ciudad
+	tour := make([]int, n)
+	mask := completo
+	for pos := n - 1; pos >= 1; pos-- {
+		tour[pos] = ultimo + 1
+		anterior := int(padre[mask*m+ultimo])
+		mask &^= 1 << ultimo
+		ultimo = anterior
+	}
+	return tour, mejor, nil
+}
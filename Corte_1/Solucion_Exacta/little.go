@@ -0,0 +1,304 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"solucion_exacta/tsp"
+)
+
+// busquedaLittle es el estado del algoritmo de Little et al. (1963).
+//
+// Cada nodo es una matriz de costos reducida: se resta el mínimo de cada
+// fila y de cada columna y la suma de lo restado es la cota inferior. Se
+// ramifica sobre el cero (i, j) de mayor arrepentimiento (lo que costaría no
+// usarlo: el menor de su fila sin j más el menor de su columna sin i), con
+// un hijo que incluye la arista i -> j y otro que la prohíbe. Al incluir una
+// arista se prohíbe la que cerraría un subtour con el camino que se forma.
+// Nada supone simetría, así que resuelve instancias ATSP directamente.
+type busquedaLittle struct {
+	distances [][]float64
+	n         int
+	enteras   bool
+	lim       limite
+
+	mejorTour  []int
+	mejorCosto float64
+
+	explorados     int
+	podados        int
+	detenido       bool
+	cotaAbandonada float64
+	ultimoResumen  time.Time
+}
+
+// nodoLittle es el estado de un nodo: la matriz reducida (Inf = prohibida),
+// las filas y columnas que siguen activas, las aristas incluidas y los
+// extremos de los caminos que forman
+type nodoLittle struct {
+	m         [][]float64
+	fila, col []bool
+	sig       []int // sig[i] = j si i -> j está incluida, -1 si no
+	finDe     []int // finDe[s] = último vértice del camino que empieza en s
+	inicioDe  []int // inicioDe[e] = primer vértice del camino que termina en e
+	incluidas int
+	cota      float64
+}
+
+// copiar devuelve una copia profunda del nodo
+func (nd *nodoLittle) copiar() *nodoLittle {
+	c := &nodoLittle{
+		m:         make([][]float64, len(nd.m)),
+		fila:      append([]bool(nil), nd.fila...),
+		col:       append([]bool(nil), nd.col...),
+		sig:       append([]int(nil), nd.sig...),
+		finDe:     append([]int(nil), nd.finDe...),
+		inicioDe:  append([]int(nil), nd.inicioDe...),
+		incluidas: nd.incluidas,
+		cota:      nd.cota,
+	}
+	for i, r := range nd.m {
+		c.m[i] = append([]float64(nil), r...)
+	}
+	return c
+}
+
+// reducir resta el mínimo de cada fila y columna activa y lo suma a la cota.
+// Devuelve false si alguna fila o columna quedó sin aristas permitidas.
+func (nd *nodoLittle) reducir() bool {
+	n := len(nd.m)
+	for i := 0; i < n; i++ {
+		if !nd.fila[i] {
+			continue
+		}
+		mn := math.Inf(1)
+		for j := 0; j < n; j++ {
+			if nd.col[j] {
+				mn = math.Min(mn, nd.m[i][j])
+			}
+		}
+		if math.IsInf(mn, 1) {
+			return false
+		}
+		if mn > 0 {
+			for j := 0; j < n; j++ {
+				if nd.col[j] {
+					nd.m[i][j] -= mn
+				}
+			}
+			nd.cota += mn
+		}
+	}
+	for j := 0; j < n; j++ {
+		if !nd.col[j] {
+			continue
+		}
+		mn := math.Inf(1)
+		for i := 0; i < n; i++ {
+			if nd.fila[i] {
+				mn = math.Min(mn, nd.m[i][j])
+			}
+		}
+		if math.IsInf(mn, 1) {
+			return false
+		}
+		if mn > 0 {
+			for i := 0; i < n; i++ {
+				if nd.fila[i] {
+					nd.m[i][j] -= mn
+				}
+			}
+			nd.cota += mn
+		}
+	}
+	return true
+}
+
+// incluir agrega la arista i -> j: quita la fila i y la columna j y prohíbe
+// la arista que cerraría el camino resultante antes de tiempo
+func (nd *nodoLittle) incluir(i, j int) {
+	n := len(nd.m)
+	nd.sig[i] = j
+	nd.fila[i], nd.col[j] = false, false
+	nd.incluidas++
+
+	s, e := nd.inicioDe[i], nd.finDe[j]
+	nd.finDe[s], nd.inicioDe[e] = e, s
+	if nd.incluidas < n-1 {
+		nd.m[e][s] = math.Inf(1)
+	}
+}
+
+// elegirArista devuelve el cero de mayor arrepentimiento y ese valor
+func (nd *nodoLittle) elegirArista() (int, int, float64) {
+	n := len(nd.m)
+	// Dos menores de cada fila y columna activas, para obtener el menor
+	// "sin contar (i, j)" en O(1)
+	min1F, min2F := make([]float64, n), make([]float64, n)
+	argF := make([]int, n)
+	min1C, min2C := make([]float64, n), make([]float64, n)
+	argC := make([]int, n)
+	for k := 0; k < n; k++ {
+		min1F[k], min2F[k], min1C[k], min2C[k] = math.Inf(1), math.Inf(1), math.Inf(1), math.Inf(1)
+	}
+	for i := 0; i < n; i++ {
+		if !nd.fila[i] {
+			continue
+		}
+		for j := 0; j < n; j++ {
+			if !nd.col[j] {
+				continue
+			}
+			v := nd.m[i][j]
+			if v < min1F[i] {
+				min1F[i], min2F[i], argF[i] = v, min1F[i], j
+			} else if v < min2F[i] {
+				min2F[i] = v
+			}
+			if v < min1C[j] {
+				min1C[j], min2C[j], argC[j] = v, min1C[j], i
+			} else if v < min2C[j] {
+				min2C[j] = v
+			}
+		}
+	}
+
+	bi, bj, mejor := -1, -1, -1.0
+	for i := 0; i < n; i++ {
+		if !nd.fila[i] {
+			continue
+		}
+		for j := 0; j < n; j++ {
+			if !nd.col[j] || nd.m[i][j] != 0 {
+				continue
+			}
+			r := min1F[i]
+			if argF[i] == j {
+				r = min2F[i]
+			}
+			c := min1C[j]
+			if argC[j] == i {
+				c = min2C[j]
+			}
+			if a := r + c; a > mejor {
+				bi, bj, mejor = i, j, a
+			}
+		}
+	}
+	return bi, bj, mejor
+}
+
+// TSPLittle resuelve el TSP (simétrico o asimétrico) con el algoritmo de
+// Little: búsqueda en profundidad que explora primero el hijo que incluye la
+// arista elegida. La cota superior inicial es el mejor vecino más cercano,
+// que respeta la dirección de las aristas. Solo se usan TimeLimit y
+// NodeLimit de config.
+func TSPLittle(distances [][]float64, config BBConfig) BBResult {
+	n := len(distances)
+	inst := &tsp.Instance{NumCities: n, Distance: distances}
+	tour, costo := tsp.BestNearestNeighbor(inst)
+	fmt.Printf("Cota superior inicial (mejor vecino mas cercano): %.2f\n", costo)
+
+	b := &busquedaLittle{
+		distances:      distances,
+		n:              n,
+		enteras:        distanciasEnteras(distances),
+		lim:            limite{inicio: time.Now(), tiempo: config.TimeLimit, nodos: int64(config.NodeLimit)},
+		mejorTour:      tour,
+		mejorCosto:     costo,
+		cotaAbandonada: math.Inf(1),
+		ultimoResumen:  time.Now(),
+	}
+	if n < 3 {
+		return resumenBusqueda(tour, costo, costo, costo, true, b.enteras, 0, 0)
+	}
+
+	raiz := &nodoLittle{
+		m:        make([][]float64, n),
+		fila:     make([]bool, n),
+		col:      make([]bool, n),
+		sig:      make([]int, n),
+		finDe:    make([]int, n),
+		inicioDe: make([]int, n),
+	}
+	for i := 0; i < n; i++ {
+		raiz.m[i] = append([]float64(nil), distances[i]...)
+		raiz.m[i][i] = math.Inf(1)
+		raiz.fila[i], raiz.col[i] = true, true
+		raiz.sig[i], raiz.finDe[i], raiz.inicioDe[i] = -1, i, i
+	}
+	raiz.reducir()
+	cotaRaiz := raiz.cota // explorar reutiliza raiz para el hijo que excluye
+	fmt.Printf("Cota inferior en la raiz (matriz reducida): %.2f\n", cotaRaiz)
+
+	fmt.Println("Iniciando busqueda")
+	b.explorar(raiz)
+
+	cota := b.mejorCosto
+	if b.detenido {
+		cota = math.Min(cota, b.cotaAbandonada)
+	}
+	return resumenBusqueda(b.mejorTour, b.mejorCosto, cota, cotaRaiz, !b.detenido, b.enteras, b.explorados, b.podados)
+}
+
+// explorar resuelve el subárbol de nd, que ya está reducido. Puede
+// modificar nd.
+func (b *busquedaLittle) explorar(nd *nodoLittle) {
+	if podable(nd.cota, b.mejorCosto, b.enteras) {
+		b.podados++
+		return
+	}
+	if b.detenido || b.lim.alcanzado(int64(b.explorados)) {
+		b.detenido = true
+		b.cotaAbandonada = math.Min(b.cotaAbandonada, nd.cota)
+		return
+	}
+	b.explorados++
+	if time.Since(b.ultimoResumen) >= ResumenCada {
+		imprimirResumen(time.Since(b.lim.inicio), int64(b.explorados), 0, b.mejorCosto, nd.cota)
+		b.ultimoResumen = time.Now()
+	}
+
+	// Tour completo
+	if nd.incluidas == b.n {
+		tour := make([]int, 0, b.n)
+		for c := 0; len(tour) < b.n; c = nd.sig[c] {
+			tour = append(tour, c)
+		}
+		costo := (&tsp.Instance{NumCities: b.n, Distance: b.distances}).TourLength(tour)
+		if costo < b.mejorCosto {
+			b.mejorTour, b.mejorCosto = tour, costo
+			fmt.Printf("Nueva mejor solución costo: %.2f | Nodos: %d\n", costo, b.explorados)
+		}
+		return
+	}
+
+	i, j, arrepentimiento := nd.elegirArista()
+	if i < 0 {
+		b.podados++
+		return
+	}
+
+	// Incluir i -> j
+	hijo := nd.copiar()
+	hijo.incluir(i, j)
+	if hijo.reducir() {
+		b.explorar(hijo)
+	} else {
+		b.podados++
+	}
+
+	// Excluir i -> j: la cota sube al menos el arrepentimiento; se reutiliza
+	// la matriz del padre, que ya no hace falta
+	if math.IsInf(arrepentimiento, 1) {
+		b.podados++
+		return
+	}
+	nd.m[i][j] = math.Inf(1)
+	if nd.reducir() {
+		b.explorar(nd)
+	} else {
+		b.podados++
+	}
+}
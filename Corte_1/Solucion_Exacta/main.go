@@ -2,7 +2,6 @@ package main
 
 import (
 	"math"
-	"sort"
 	"container/heap"
 
 	"fmt"
@@ -14,90 +13,9 @@ import (
 	"time"
 
 	"solucion_exacta/tsp"
-)
-
-
-// Calcula el Lower Bound (límite inferior) para un nodo dado.
-// 
-// Estrategia:
-// 1. Para cada ciudad, sumamos las dos aristas de menor costo (entrada y salida)
-// 2. Para ciudades en el camino fijo, usamos las aristas comprometidas
-// 3. Dividimos entre 2 porque cada arista se cuenta dos veces
-//
-// Distances: una matriz de slices de flotantes con las distancias entre cada par de nodos
-// path: el camino actual, cada ciudad es un entero
-// visited: un diccionario donde la llave es el entero que representa la ciudad e indica si ha sido visitada o no
-// currentCost: el costo actual del tour
-func CalculateLowerBound(distances [][]float64, path []int, visited map[int]bool, currentCost float64) float64 {
-
-	n := len(distances)
-
-	// Empezamos con el costo del camino ya recorrido
-	lb := currentCost
-
-	// Para el cálculo optimista del costo restante
-	minCostSum := 0.0
-
-	// Identificar ciudades no visitadas
-	unvisited := make([]int, 0)
-
-	for i := 0; i < n; i++ {
-		if !visited[i] {
-			unvisited = append(unvisited, i)
-		}
-	}
-
-	// Para cada ciudad no visitada, sumamos sus dos aristas más baratas
-	for _, city := range unvisited {
-
-		// Obtenemos todas las distancias desde/hacia esta ciudad
-		edges := make([]float64, 0, n-1)
-
-		for otherCity := 0; otherCity < n; otherCity++ {
-			if otherCity != city {
-				edges = append(edges, distances[city][otherCity])
-			}
-		}
-		
-		sort.Float64s(edges)
-		// Sumamos las dos más pequeñas (estrategia de aproximación de 1-Tree reducida)
-		if len(edges) >= 2 {
-			minCostSum += edges[0] + edges[1]
-		}
-	}
-
-	// Lógica para conectar el camino actual con el futuro y el regreso al inicio
-
-	// Para la última ciudad en el path actual, necesitamos considerar 
-    // una arista de salida hacia ciudades no visitadas
-	if len(path) > 0 && len(unvisited) > 0 {
-
-		lastCity := path[len(path)-1]
-		
-		// Mínimo desde la última ciudad del path a una no visitada
-		minToUnvisited := math.MaxFloat64
-		for _, v := range unvisited {
-			if distances[lastCity][v] < minToUnvisited {
-				minToUnvisited = distances[lastCity][v]
-			}
-		}
-
-		minCostSum += minToUnvisited
-
-		// Mínimo desde una no visitada de regreso al inicio (ciudad 0)
-		minToStart := math.MaxFloat64
-		for _, v := range unvisited {
-			if distances[v][0] < minToStart {
-				minToStart = distances[v][0]
-			}
-		}
-		minCostSum += minToStart
-	}
 
-	// Cada arista se cuenta dos veces (ida y vuelta conceptual)
-	return lb + (minCostSum / 2.0)
-
-}
+	heuristica "heuristica/tsp"
+)
 
 
 // Item representa un nodo en el árbol de búsqueda 
@@ -108,6 +26,7 @@ type Item struct {
 	path        []int
 	visited     map[int]bool
 	actualCost  float64
+	pi          []float64 // penalizaciones de Held-Karp con las que se obtuvo lowerBound
 
 	// Requerido por la interfaz heap
 	index       int 
@@ -136,29 +55,94 @@ func (pq *PriorityQueue) Pop() interface{} {
 }
 
 
+// BBConfig contiene los parámetros de TSPBranchBoundWithLB
+type BBConfig struct {
+	Mode      string        // Estrategia de búsqueda: ModoMejorPrimero o ModoProfundidad
+	Workers   int           // Goroutines que comparten el árbol (solo ModoProfundidad)
+	TimeLimit time.Duration // Tiempo máximo de búsqueda (0 = sin límite)
+	NodeLimit int           // Máximo de nodos explorados (0 = sin límite)
+}
+
+// BBResult es el resultado de TSPBranchBoundWithLB. Si la búsqueda se cortó
+// por un límite, Tour es el mejor tour encontrado y LowerBound la menor cota
+// entre los nodos que quedaron abiertos, así que el óptimo está entre ambos.
+type BBResult struct {
+	Tour          []int
+	Cost          float64
+	LowerBound    float64 // Cota inferior global probada
+	RootBound     float64 // Cota de Held-Karp en la raíz
+	Gap           float64 // (Cost - LowerBound) / Cost en %
+	Optimal       bool    // La búsqueda terminó: Cost es óptimo
+	NodesExplored int
+	NodesPruned   int
+}
+
+// ResumenCada es el intervalo entre los resúmenes que se imprimen durante
+// la búsqueda
+const ResumenCada = time.Second
+
+// limite decide cuándo cortar la búsqueda según BBConfig
+type limite struct {
+	inicio time.Time
+	tiempo time.Duration
+	nodos  int64
+}
+
+func (l limite) alcanzado(nodos int64) bool {
+	return (l.nodos > 0 && nodos >= l.nodos) || (l.tiempo > 0 && time.Since(l.inicio) >= l.tiempo)
+}
+
+// imprimirResumen muestra el estado de la búsqueda en una línea
+func imprimirResumen(transcurrido time.Duration, nodos int64, abiertos int, incumbente, cota float64) {
+	fmt.Printf("[%7.1fs] Nodos: %d | Abiertos: %d | Incumbente: %.2f | Cota: %.2f | Gap: %.2f%%\n",
+		transcurrido.Seconds(), nodos, abiertos, incumbente, cota, (incumbente-cota)/incumbente*100)
+}
+
 // Resuelve el TSP usando Branch and Bound con Lower Bound y Best-First Search.
     
 // Args:
 //     distances: Matriz de adyacencia con los pesos de las aristas
 //     node_names: Lista con los nombres de los nodos (opcional)
+//     config: Estrategia, workers y límites de la búsqueda
 // Retorna:
-//     - best_path: Lista con el orden óptimo de nodos
-//     - best_cost: Costo total del tour óptimo    
-func TSPBranchBoundWithLB(distances [][]float64) ([]int, float64) {
+//     - BBResult con el mejor tour, su costo, la cota inferior global y el gap
+func TSPBranchBoundWithLB(distances [][]float64, config BBConfig) BBResult {
 
 	n := len(distances)
-	var bestPath []int
-	bestCost := math.Inf(1)
+	lim := limite{inicio: time.Now(), tiempo: config.TimeLimit, nodos: int64(config.NodeLimit)}
+	enteras := distanciasEnteras(distances)
 
-	// <----- Aqui va una logica de strings que voy a quitar --->
+	// Cota superior inicial: Farthest Insertion mejorado con 2-opt. El
+	// incumbente arranca con ese tour en lugar de +Inf, así se poda desde el
+	// primer nodo y el subgradiente tiene una cota superior para el paso
+	bestPath, bestCost := cotaSuperiorInicial(distances)
+	fmt.Printf("Cota superior inicial (Farthest Insertion + 2-opt): %.2f\n", bestCost)
 
 	// Nodo inicial visitado: el 0
 	initialVisited := map[int]bool{0: true}
 	initialPath := []int{0}
 
-	// Calculamos el LB inicial para el nodo raíz
-	initialLB := CalculateLowerBound(distances, initialPath, initialVisited, 0)
+	// Cota de Held-Karp en la raíz: 1-árbol con penalizaciones optimizadas
+	libresRaiz := make([]int, 0, n-1)
+	for c := 1; c < n; c++ {
+		libresRaiz = append(libresRaiz, c)
+	}
+	initialLB, initialPi := Subgradiente(distances, 0, 0, libresRaiz, make([]float64, n), IteracionesRaiz, 2, bestCost)
+	fmt.Printf("Cota inferior en la raiz (Held-Karp): %.2f\n", initialLB)
 
+	nodesExplored := 0
+	nodesPruned := 0
+
+	if config.Mode == ModoProfundidad {
+		b := nuevaBusquedaProfundidad(distances, enteras, bestPath, bestCost, lim, initialLB)
+		fmt.Println("Iniciando busqueda")
+		cota := bestCost
+		if !podable(initialLB, bestCost, enteras) {
+			bestPath, bestCost, cota = b.ejecutar(tarea{camino: initialPath, lb: initialLB, pi: initialPi}, config.Workers)
+		}
+		nodesExplored, nodesPruned = int(b.explorados.Load()), int(b.podados.Load())
+		return resumenBusqueda(bestPath, bestCost, cota, initialLB, !b.detenido.Load(), enteras, nodesExplored, nodesPruned)
+	}
 
 	// Usamos una cola de prioridad (min-heap) para Best-First Search
 	pq := &PriorityQueue{}
@@ -169,19 +153,26 @@ func TSPBranchBoundWithLB(distances [][]float64) ([]int, float64) {
 		path:        initialPath,
 		visited:     initialVisited,
 		actualCost:  0,
+		pi:          initialPi,
 	})
 
-	nodesExplored := 0
-	nodesPruned := 0
-
-	println("Iniciando busqueda")
-	var iter = 0
+	fmt.Println("Iniciando busqueda")
+	completo := true
+	ultimoResumen := time.Now()
 
 	for pq.Len() > 0 {
-		
-		fmt.Printf("Iteracion: %d\n", iter)
-		iter++
-		fmt.Printf("#Items en la cola de prioridad: %d\n", pq.Len())
+
+		// Límite de tiempo o nodos: la cola queda con los nodos abiertos
+		if lim.alcanzado(int64(nodesExplored)) {
+			completo = false
+			break
+		}
+
+		// Resumen periódico; el tope del heap es la cota global
+		if time.Since(ultimoResumen) >= ResumenCada {
+			imprimirResumen(time.Since(lim.inicio), int64(nodesExplored), pq.Len(), bestCost, min(bestCost, (*pq)[0].lowerBound))
+			ultimoResumen = time.Now()
+		}
 
 		// Extraemos el nodo con menor Lower Bound (Best-First Search)
 		// Hacer pop en nuestra implementacion devuelve interface{}, hay que convertirlo a *Item
@@ -191,7 +182,7 @@ func TSPBranchBoundWithLB(distances [][]float64) ([]int, float64) {
 		// Poda: Si el LB de este nodo es mayor o igual al mejor costo conocido,
         // no puede contener una solución mejor
 
-		if node.lowerBound >= bestCost {
+		if podable(node.lowerBound, bestCost, enteras) {
 			nodesPruned++
 			continue
 		}
@@ -238,17 +229,25 @@ func TSPBranchBoundWithLB(distances [][]float64) ([]int, float64) {
 
 				newVisited[nextCity] = true
 
-				// Calculamos el Lower Bound para este nodo hijo
-				newLB := CalculateLowerBound(distances, newPath, newVisited, newActualCost)
+				// Calculamos el Lower Bound para este nodo hijo, partiendo de
+				// las penalizaciones del padre
+				libres := make([]int, 0, n-len(newPath))
+				for c := 0; c < n; c++ {
+					if !newVisited[c] {
+						libres = append(libres, c)
+					}
+				}
+				newLB, newPi := Subgradiente(distances, newActualCost, nextCity, libres, node.pi, IteracionesHijo, 0.5, bestCost)
 
 				// Poda: solo agregamos el nodo si su LB es mejor que el mejor costo actual
-				if newLB < bestCost {
+				if !podable(newLB, bestCost, enteras) {
 					heap.Push(pq, &Item{
 						lowerBound:  newLB,
 						currentCity: nextCity,
 						path:        newPath,
 						visited:     newVisited,
 						actualCost:  newActualCost,
+						pi:          newPi,
 					})
 				} else {
 					nodesPruned++
@@ -259,21 +258,146 @@ func TSPBranchBoundWithLB(distances [][]float64) ([]int, float64) {
 
 
 	}
-	
-	fmt.Printf("\nFinalizado. Nodos explorados: %d, Podados: %d\n", nodesExplored, nodesPruned)
+
+	// Si se cortó, la cota global es la menor entre los nodos abiertos
+	cota := bestCost
+	if !completo && pq.Len() > 0 {
+		cota = min(bestCost, (*pq)[0].lowerBound)
+	}
+	return resumenBusqueda(bestPath, bestCost, cota, initialLB, completo, enteras, nodesExplored, nodesPruned)
+
+}
+
+// resumenBusqueda imprime las estadísticas finales de la búsqueda y arma
+// el resultado. cota es la cota inferior global (bestCost si completo)
+func resumenBusqueda(bestPath []int, bestCost, cota, initialLB float64, completo, enteras bool, nodesExplored, nodesPruned int) BBResult {
+	if enteras {
+		cota = min(bestCost, math.Ceil(cota-1e-6))
+	}
+	gap := (bestCost - cota) / bestCost * 100
+
+	if completo {
+		fmt.Printf("\nFinalizado. Nodos explorados: %d, Podados: %d\n", nodesExplored, nodesPruned)
+	} else {
+		fmt.Printf("\nLimite alcanzado. Nodos explorados: %d, Podados: %d\n", nodesExplored, nodesPruned)
+	}
 	fmt.Printf("\nTotal de nodos considerados: %d\n", nodesExplored + nodesPruned)
 
+	// El gap de la raíz mide qué tan ajustada era la cota de Held-Karp; el de
+	// optimalidad es 0 si la búsqueda terminó
+	fmt.Printf("Cota inferior en la raiz: %.2f, gap raiz: %.2f%%, cota global: %.2f, gap de optimalidad: %.2f%%\n",
+		initialLB, (bestCost-initialLB)/bestCost*100, cota, gap)
+
+	return BBResult{
+		Tour:          bestPath,
+		Cost:          bestCost,
+		LowerBound:    cota,
+		RootBound:     initialLB,
+		Gap:           gap,
+		Optimal:       completo,
+		NodesExplored: nodesExplored,
+		NodesPruned:   nodesPruned,
+	}
+}
+
+// podable indica si un nodo con cota lb no puede mejorar al incumbente. Con
+// distancias enteras (TSPLIB) cualquier tour cuesta un entero, así que una
+// cota de 1234.2 ya permite podar contra un incumbente de 1235
+func podable(lb, incumbente float64, enteras bool) bool {
+	if enteras {
+		return math.Ceil(lb-1e-6) >= incumbente
+	}
+	return lb >= incumbente
+}
+
+// cotaSuperiorInicial construye un tour con Farthest Insertion, lo mejora con
+// 2-opt y lo rota para que empiece en la ciudad 0, como los caminos del árbol
+func cotaSuperiorInicial(distances [][]float64) ([]int, float64) {
+	n := len(distances)
+	inst := &tsp.Instance{NumCities: n, Distance: distances}
+	tour := heuristica.FarthestInsertionFunc(n, func(i, j int) float64 { return distances[i][j] })
+	tour, costo := tsp.TwoOpt(inst, tour)
+
+	inicio := 0
+	for i, c := range tour {
+		if c == 0 {
+			inicio = i
+		}
+	}
+	return append(tour[inicio:], tour[:inicio]...), costo
+}
+
+// distanciasEnteras indica si todas las distancias son enteras
+func distanciasEnteras(distances [][]float64) bool {
+	for _, fila := range distances {
+		for _, d := range fila {
+			if d != math.Trunc(d) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// distanciasSimetricas indica si la matriz es simétrica (fuera de la diagonal)
+func distanciasSimetricas(distances [][]float64) bool {
+	for i := range distances {
+		for j := i + 1; j < len(distances); j++ {
+			if distances[i][j] != distances[j][i] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Métodos exactos disponibles en la línea de comandos
+const (
+	MetodoBB     = "bb"     // Branch and Bound (TSPBranchBoundWithLB)
+	MetodoDP     = "dp"     // Programación dinámica de Held-Karp (HeldKarp)
+	MetodoAmbos  = "both"   // Ambos, comparando los costos
+	MetodoCorte  = "cut"    // Planos de corte DFJ con ramificación (TSPPlanosCorte)
+	MetodoLittle = "little" // Little: ramificación por aristas, admite ATSP (TSPLittle)
+)
 
-	return bestPath, bestCost
+// Metodos lista los métodos válidos
+var Metodos = []string{MetodoBB, MetodoDP, MetodoAmbos, MetodoCorte, MetodoLittle}
 
+// ValidarMetodo devuelve un error si metodo no es un método conocido
+func ValidarMetodo(metodo string) error {
+	for _, m := range Metodos {
+		if m == metodo {
+			return nil
+		}
+	}
+	return fmt.Errorf("metodo exacto desconocido %q (opciones: %v)", metodo, Metodos)
 }
 
 func main() {
 	// CLI flags
-	tspFile := flag.String("tsp", "", "Path to TSPLIB .tsp file (e.g., berlin52.tsp)")
+	tspFile := flag.String("tsp", "", "Path to TSPLIB .tsp or .atsp file (e.g., berlin52.tsp)")
+	modo := flag.String("search", ModoMejorPrimero, "Search strategy: best (best-first) or dfs (depth-first, low memory)")
+	workers := flag.Int("workers", 1, "Number of parallel workers (dfs only)")
+	timeLimit := flag.Duration("time", 0, "Time limit, e.g. 30s or 5m (0 = no limit)")
+	nodeLimit := flag.Int("nodes", 0, "Explored node limit (0 = no limit)")
+	metodo := flag.String("method", MetodoBB, "Exact method: bb (Branch & Bound), dp (Held-Karp DP, small n), both (cross-check), cut (DFJ branch & cut) or little (Little's edge branching, ATSP)")
 
 	flag.Parse()
 
+	if err := ValidarMetodo(*metodo); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := ValidarModo(*modo); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if *workers < 1 || (*workers > 1 && *modo != ModoProfundidad) {
+		fmt.Fprintf(os.Stderr, "Error: -workers must be >= 1, and > 1 requires -search %s\n", ModoProfundidad)
+		os.Exit(1)
+	}
+
 	if *tspFile == "" {
 		fmt.Fprintf(os.Stderr, "Error: must specify -tsp <file.tsp>\n")
 		fmt.Fprintf(os.Stderr, "Usage: %s -tsp <file.tsp>\n", os.Args[0])
@@ -287,7 +411,7 @@ func main() {
 		os.Exit(1)
 	}
 
-	instanceName := strings.TrimSuffix(filepath.Base(*tspFile), ".tsp")
+	instanceName := strings.TrimSuffix(strings.TrimSuffix(filepath.Base(*tspFile), ".tsp"), ".atsp")
 	
 
 	fmt.Printf("\nInstance: %s (%d cities)\n", instanceName, inst.NumCities)
@@ -298,10 +422,54 @@ func main() {
 	}
 	fmt.Println()
 
-	// Run 
+	// bb y cut suponen simetría; dp y little no
+	if !distanciasSimetricas(inst.Distance) && *metodo != MetodoDP && *metodo != MetodoLittle {
+		fmt.Fprintf(os.Stderr, "Error: asymmetric instance, use -method %s or %s\n", MetodoLittle, MetodoDP)
+		os.Exit(1)
+	}
+
+	// dp y both necesitan Held-Karp: se rechazan antes de correr el B&B de both
+	if (*metodo == MetodoDP || *metodo == MetodoAmbos) && inst.NumCities > MaxCiudadesHeldKarp {
+		fmt.Fprintf(os.Stderr, "Error: -method %s uses Held-Karp, limited to %d cities (instance has %d); use -method %s\n",
+			*metodo, MaxCiudadesHeldKarp, inst.NumCities, MetodoBB)
+		os.Exit(1)
+	}
+
+	// Run
 	start := time.Now()
 
-	bestPath, bestCost := TSPBranchBoundWithLB(inst.Distance)
+	var result BBResult
+	if *metodo == MetodoCorte {
+		result = TSPPlanosCorte(inst.Distance, BBConfig{TimeLimit: *timeLimit, NodeLimit: *nodeLimit})
+	} else if *metodo == MetodoLittle {
+		result = TSPLittle(inst.Distance, BBConfig{TimeLimit: *timeLimit, NodeLimit: *nodeLimit})
+	} else if *metodo != MetodoDP {
+		result = TSPBranchBoundWithLB(inst.Distance, BBConfig{
+			Mode:      *modo,
+			Workers:   *workers,
+			TimeLimit: *timeLimit,
+			NodeLimit: *nodeLimit,
+		})
+	}
+	if *metodo == MetodoDP || *metodo == MetodoAmbos {
+		tour, costo, err := HeldKarp(inst.Distance)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if *metodo == MetodoDP {
+			result = BBResult{Tour: tour, Cost: costo, LowerBound: costo, Optimal: true}
+		} else {
+			estado := "OK"
+			if result.Optimal && math.Abs(result.Cost-costo) > 1e-6 {
+				estado = "MISMATCH"
+			} else if !result.Optimal && (costo > result.Cost+1e-6 || costo < result.LowerBound-1e-6) {
+				estado = "MISMATCH"
+			}
+			fmt.Printf("Cross-check (Held-Karp DP): %.2f -> %s\n", costo, estado)
+		}
+	}
+	bestPath, bestCost := result.Tour, result.Cost
 
 	elapsed := time.Since(start)
 
@@ -315,6 +483,12 @@ func main() {
 	// Print results
 	fmt.Println("Results:")
 	fmt.Printf("  Best tour length: %.0f\n", bestCost)
+	fmt.Printf("  Lower bound: %.2f\n", result.LowerBound)
+	if result.Optimal {
+		fmt.Println("  Status: optimal")
+	} else {
+		fmt.Printf("  Status: limit reached, proven gap %.2f%%\n", result.Gap)
+	}
 	if inst.OptimalCost > 0 {
 		fmt.Printf("  Optimal length: %.0f\n", inst.OptimalCost)
 		fmt.Printf("  Gap: %.2f%%\n", gap)
@@ -322,5 +496,5 @@ func main() {
 
 	fmt.Printf("  Time: %v\n", elapsed)
 	fmt.Printf("  Tour: %v\n", bestPath)
-	println("")
+	fmt.Println()
 }
\ No newline at end of file
@@ -0,0 +1,534 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// Tolerancias del simplex
+const (
+	tolPrimal    = 1e-7 // violación de cota aceptada en las variables básicas
+	tolDual      = 1e-9 // costo reducido con signo incorrecto aceptado
+	tolPivote    = 1e-9 // |alpha| mínimo para entrar a la base
+	refactorCada = 200  // pivotes entre refactorizaciones de la inversa
+)
+
+// Estados de una variable respecto de la base
+const (
+	basica int8 = iota
+	enInferior
+	enSuperior
+)
+
+// Resultados de lp.resolver
+const (
+	lpOptimo = iota
+	lpInfactible
+	lpLimite
+)
+
+// lp es un programa lineal min c·x, A·x + s = rhs, inf <= x <= sup,
+// infL <= s <= supL, resuelto con simplex dual revisado con la inversa de
+// la base explícita (denso, m×m).
+//
+// Es específico del TSP: todas las entradas de A valen 1 (restricciones de
+// grado y de eliminación de subtours), así que una columna es solo la lista
+// de filas donde aparece. Cada fila k tiene su variable de holgura s_k
+// (lógica) con coeficiente 1. Las columnas estructurales siempre tienen
+// cotas finitas, así que cualquier base puede hacerse dual factible poniendo
+// cada no básica en la cota que indica el signo de su costo reducido: por eso
+// agregar columnas, filas o cambiar cotas nunca requiere simplex primal.
+type lp struct {
+	m   int
+	rhs []float64
+
+	// Columnas estructurales
+	costo, inf, sup, x, d []float64
+	estado                []int8
+	filas                 [][]int
+
+	// Variables lógicas, una por fila
+	infL, supL, xL, dL []float64
+	estadoL            []int8
+
+	// base[r] es la variable básica de la posición r: j >= 0 estructural,
+	// ^k (negativo) la lógica de la fila k
+	base    []int
+	binv    [][]float64
+	pivotes int
+
+	// Si resolver termina con lpInfactible, rayo es la fila de B^-1 de la
+	// variable que no pudo volver a sus cotas y subirRayo si debía subir:
+	// una columna nueva con alpha = rayo·a_j del signo adecuado podría
+	// hacerla factible (pricing de Farkas)
+	rayo      []float64
+	subirRayo bool
+}
+
+// columna devuelve las filas de la variable v (estructural o lógica)
+func (p *lp) columna(v int) []int {
+	if v >= 0 {
+		return p.filas[v]
+	}
+	return []int{^v}
+}
+
+// valor y fijarValor acceden al valor de cualquier variable
+func (p *lp) valor(v int) float64 {
+	if v >= 0 {
+		return p.x[v]
+	}
+	return p.xL[^v]
+}
+
+func (p *lp) fijarValor(v int, val float64) {
+	if v >= 0 {
+		p.x[v] = val
+	} else {
+		p.xL[^v] = val
+	}
+}
+
+// cotas devuelve las cotas de cualquier variable
+func (p *lp) cotas(v int) (float64, float64) {
+	if v >= 0 {
+		return p.inf[v], p.sup[v]
+	}
+	return p.infL[^v], p.supL[^v]
+}
+
+// agregarFila agrega la fila sum(x_j, j en cols) + s = rhs con
+// infL <= s <= supL. La holgura entra a la base, así que la base sigue
+// siendo dual factible; si la fila está violada la resuelve el simplex dual.
+func (p *lp) agregarFila(cols []int, rhs, infL, supL float64) int {
+	k := p.m
+	p.m++
+	p.rhs = append(p.rhs, rhs)
+	enFila := make(map[int]bool, len(cols))
+	s := rhs
+	for _, j := range cols {
+		p.filas[j] = append(p.filas[j], k)
+		enFila[j] = true
+		s -= p.x[j]
+	}
+
+	// Nueva inversa: [[B^-1, 0], [-a_B B^-1, 1]], donde a_B son los
+	// coeficientes de la fila en las variables básicas
+	nueva := make([]float64, p.m)
+	for r, v := range p.base {
+		if v >= 0 && enFila[v] {
+			for c, b := range p.binv[r] {
+				nueva[c] -= b
+			}
+		}
+	}
+	nueva[k] = 1
+	for r := range p.binv {
+		p.binv[r] = append(p.binv[r], 0)
+	}
+	p.binv = append(p.binv, nueva)
+
+	p.infL = append(p.infL, infL)
+	p.supL = append(p.supL, supL)
+	p.xL = append(p.xL, s)
+	p.dL = append(p.dL, 0)
+	p.estadoL = append(p.estadoL, basica)
+	p.base = append(p.base, ^k)
+	return k
+}
+
+// agregarColumnas agrega columnas estructurales con cotas [0, 1], cada una
+// no básica en la cota que la deja dual factible. Devuelve sus índices.
+func (p *lp) agregarColumnas(costos []float64, filas [][]int) []int {
+	y := p.duales()
+	idx := make([]int, len(costos))
+	for i, c := range costos {
+		j := len(p.costo)
+		idx[i] = j
+		dj := c
+		for _, k := range filas[i] {
+			dj -= y[k]
+		}
+		p.costo = append(p.costo, c)
+		p.inf = append(p.inf, 0)
+		p.sup = append(p.sup, 1)
+		p.filas = append(p.filas, filas[i])
+		p.d = append(p.d, dj)
+		if dj < 0 {
+			p.x = append(p.x, 1)
+			p.estado = append(p.estado, enSuperior)
+		} else {
+			p.x = append(p.x, 0)
+			p.estado = append(p.estado, enInferior)
+		}
+	}
+	p.recalcularBasicas()
+	return idx
+}
+
+// fijarCotas cambia las cotas de la columna j. Si no es básica se mueve a la
+// cota que indica su costo reducido. Hay que llamar a recalcularBasicas
+// después de cambiar cotas.
+func (p *lp) fijarCotas(j int, inf, sup float64) {
+	p.inf[j], p.sup[j] = inf, sup
+	if p.estado[j] == basica {
+		return
+	}
+	if p.d[j] < 0 {
+		p.estado[j], p.x[j] = enSuperior, sup
+	} else {
+		p.estado[j], p.x[j] = enInferior, inf
+	}
+}
+
+// duales devuelve y = c_B B^-1
+func (p *lp) duales() []float64 {
+	y := make([]float64, p.m)
+	for r, v := range p.base {
+		if v < 0 || p.costo[v] == 0 {
+			continue
+		}
+		c := p.costo[v]
+		for k, b := range p.binv[r] {
+			y[k] += c * b
+		}
+	}
+	return y
+}
+
+// objetivo es c·x
+func (p *lp) objetivo() float64 {
+	z := 0.0
+	for j, c := range p.costo {
+		z += c * p.x[j]
+	}
+	return z
+}
+
+// recalcularBasicas calcula x_B = B^-1 (rhs - N x_N)
+func (p *lp) recalcularBasicas() {
+	b := append([]float64(nil), p.rhs...)
+	for j, e := range p.estado {
+		if e != basica && p.x[j] != 0 {
+			for _, k := range p.filas[j] {
+				b[k] -= p.x[j]
+			}
+		}
+	}
+	for k, e := range p.estadoL {
+		if e != basica {
+			b[k] -= p.xL[k]
+		}
+	}
+	for r, v := range p.base {
+		s := 0.0
+		for k, bk := range p.binv[r] {
+			s += bk * b[k]
+		}
+		p.fijarValor(v, s)
+	}
+}
+
+// recalcular refactoriza la inversa y recalcula valores y costos reducidos
+// desde cero para acotar el error numérico acumulado
+func (p *lp) recalcular() error {
+	if err := p.refactorizar(); err != nil {
+		return err
+	}
+	y := p.duales()
+	for j, e := range p.estado {
+		if e == basica {
+			p.d[j] = 0
+			continue
+		}
+		dj := p.costo[j]
+		for _, k := range p.filas[j] {
+			dj -= y[k]
+		}
+		p.d[j] = dj
+		// Los errores de redondeo pueden dejar un costo reducido con el
+		// signo cambiado; como la cota es finita basta con cambiar de cota
+		if (e == enInferior && dj < -tolDual) || (e == enSuperior && dj > tolDual) {
+			p.fijarCotas(j, p.inf[j], p.sup[j])
+		}
+	}
+	for k, e := range p.estadoL {
+		if e == basica {
+			p.dL[k] = 0
+		} else {
+			p.dL[k] = -y[k]
+		}
+	}
+	p.recalcularBasicas()
+	p.pivotes = 0
+	return nil
+}
+
+// refactorizar invierte la base con Gauss-Jordan y pivoteo parcial
+func (p *lp) refactorizar() error {
+	m := p.m
+	a := make([][]float64, m)
+	inv := make([][]float64, m)
+	for i := range a {
+		a[i] = make([]float64, m)
+		inv[i] = make([]float64, m)
+		inv[i][i] = 1
+	}
+	for r, v := range p.base {
+		for _, k := range p.columna(v) {
+			a[k][r] = 1
+		}
+	}
+	for c := 0; c < m; c++ {
+		piv := c
+		for i := c + 1; i < m; i++ {
+			if math.Abs(a[i][c]) > math.Abs(a[piv][c]) {
+				piv = i
+			}
+		}
+		if math.Abs(a[piv][c]) < 1e-12 {
+			return fmt.Errorf("base singular en la columna %d", c)
+		}
+		a[c], a[piv] = a[piv], a[c]
+		inv[c], inv[piv] = inv[piv], inv[c]
+		f := 1 / a[c][c]
+		for k := 0; k < m; k++ {
+			a[c][k] *= f
+			inv[c][k] *= f
+		}
+		for i := 0; i < m; i++ {
+			if i == c || a[i][c] == 0 {
+				continue
+			}
+			g := a[i][c]
+			for k := 0; k < m; k++ {
+				a[i][k] -= g * a[c][k]
+				inv[i][k] -= g * inv[c][k]
+			}
+		}
+	}
+	p.binv = inv
+	return nil
+}
+
+// eliminarFilas quita las filas indicadas, cuyas holguras deben ser básicas
+// (restricciones inactivas). Devuelve para cada fila vieja su nuevo índice
+// (-1 si se eliminó).
+func (p *lp) eliminarFilas(quitar map[int]bool) ([]int, error) {
+	nuevo := make([]int, p.m)
+	m := 0
+	for k := 0; k < p.m; k++ {
+		if quitar[k] {
+			nuevo[k] = -1
+			continue
+		}
+		nuevo[k] = m
+		p.rhs[m] = p.rhs[k]
+		p.infL[m], p.supL[m] = p.infL[k], p.supL[k]
+		p.xL[m], p.dL[m], p.estadoL[m] = p.xL[k], p.dL[k], p.estadoL[k]
+		m++
+	}
+	p.rhs, p.infL, p.supL = p.rhs[:m], p.infL[:m], p.supL[:m]
+	p.xL, p.dL, p.estadoL = p.xL[:m], p.dL[:m], p.estadoL[:m]
+
+	for j, fs := range p.filas {
+		quedan := fs[:0]
+		for _, k := range fs {
+			if nuevo[k] >= 0 {
+				quedan = append(quedan, nuevo[k])
+			}
+		}
+		p.filas[j] = quedan
+	}
+	base := p.base[:0]
+	for _, v := range p.base {
+		if v < 0 {
+			if nuevo[^v] < 0 {
+				continue
+			}
+			v = ^nuevo[^v]
+		}
+		base = append(base, v)
+	}
+	p.base = base
+	p.m = m
+	return nuevo, p.recalcular()
+}
+
+// resolver aplica simplex dual hasta la optimalidad, la infactibilidad o
+// maxPivotes pivotes. La base de partida debe ser dual factible.
+func (p *lp) resolver(maxPivotes int) (int, error) {
+	alphaL := make([]float64, 0)
+	alpha := make([]float64, 0)
+	for it := 0; it < maxPivotes; it++ {
+		if p.pivotes >= refactorCada {
+			if err := p.recalcular(); err != nil {
+				return 0, err
+			}
+		}
+
+		// Fila de salida: la básica con mayor violación de cotas
+		r, peor := -1, tolPrimal
+		for i, v := range p.base {
+			lo, hi := p.cotas(v)
+			x := p.valor(v)
+			if lo-x > peor {
+				r, peor = i, lo-x
+			} else if x-hi > peor {
+				r, peor = i, x-hi
+			}
+		}
+		if r < 0 {
+			return lpOptimo, nil
+		}
+		sale := p.base[r]
+		lo, hi := p.cotas(sale)
+		subir := p.valor(sale) < lo // la básica debe subir hasta su cota inferior
+
+		// Fila r de B^-1 A para las no básicas
+		fila := p.binv[r]
+		alpha = alpha[:0]
+		for j, e := range p.estado {
+			a := 0.0
+			if e != basica {
+				for _, k := range p.filas[j] {
+					a += fila[k]
+				}
+			}
+			alpha = append(alpha, a)
+		}
+		alphaL = append(alphaL[:0], fila...)
+
+		// Test de razón de Harris: primero el paso máximo con tolerancia,
+		// luego entre los candidatos dentro de ese paso el de mayor |alpha|
+		// (más estable numéricamente)
+		candidato := func(e int8, lo, hi, a, d float64) (float64, bool) {
+			if e == basica || lo == hi || math.Abs(a) < tolPivote {
+				return 0, false
+			}
+			// La básica cambia en -a*t; t > 0 si la no básica está en su
+			// cota inferior, t < 0 si está en la superior
+			if e == enInferior && (a < 0) != subir {
+				return 0, false
+			}
+			if e == enSuperior && (a > 0) != subir {
+				return 0, false
+			}
+			if e == enInferior {
+				return math.Max(d, 0), true
+			}
+			return math.Max(-d, 0), true
+		}
+		tmax := math.Inf(1)
+		for j, e := range p.estado {
+			if dd, ok := candidato(e, p.inf[j], p.sup[j], alpha[j], p.d[j]); ok {
+				tmax = math.Min(tmax, (dd+tolDual)/math.Abs(alpha[j]))
+			}
+		}
+		for k, e := range p.estadoL {
+			if dd, ok := candidato(e, p.infL[k], p.supL[k], alphaL[k], p.dL[k]); ok {
+				tmax = math.Min(tmax, (dd+tolDual)/math.Abs(alphaL[k]))
+			}
+		}
+		if math.IsInf(tmax, 1) {
+			// Sin candidatos: el problema dual es no acotado, salvo que sea
+			// error numérico; se confirma con la inversa recién calculada
+			if p.pivotes > 0 {
+				if err := p.recalcular(); err != nil {
+					return 0, err
+				}
+				continue
+			}
+			p.rayo = append([]float64(nil), fila...)
+			p.subirRayo = subir
+			return lpInfactible, nil
+		}
+		entra, mejor := 0, -1.0
+		for j, e := range p.estado {
+			if dd, ok := candidato(e, p.inf[j], p.sup[j], alpha[j], p.d[j]); ok && dd/math.Abs(alpha[j]) <= tmax && math.Abs(alpha[j]) > mejor {
+				entra, mejor = j, math.Abs(alpha[j])
+			}
+		}
+		for k, e := range p.estadoL {
+			if dd, ok := candidato(e, p.infL[k], p.supL[k], alphaL[k], p.dL[k]); ok && dd/math.Abs(alphaL[k]) <= tmax && math.Abs(alphaL[k]) > mejor {
+				entra, mejor = ^k, math.Abs(alphaL[k])
+			}
+		}
+
+		// Actualizar costos reducidos
+		var aq, dq float64
+		if entra >= 0 {
+			aq, dq = alpha[entra], p.d[entra]
+		} else {
+			aq, dq = alphaL[^entra], p.dL[^entra]
+		}
+		theta := dq / aq
+		for j, e := range p.estado {
+			if e != basica {
+				p.d[j] -= theta * alpha[j]
+			}
+		}
+		for k, e := range p.estadoL {
+			if e != basica {
+				p.dL[k] -= theta * alphaL[k]
+			}
+		}
+
+		// Columna entrante B^-1 a_q y actualización primal
+		w := make([]float64, p.m)
+		for i, b := range p.binv {
+			for _, k := range p.columna(entra) {
+				w[i] += b[k]
+			}
+		}
+		cota := hi
+		if subir {
+			cota = lo
+		}
+		tp := (p.valor(sale) - cota) / w[r]
+		for i, v := range p.base {
+			if w[i] != 0 {
+				p.fijarValor(v, p.valor(v)-tp*w[i])
+			}
+		}
+		p.fijarValor(entra, p.valor(entra)+tp)
+		p.fijarValor(sale, cota)
+
+		// Estados
+		nuevoEstado := enSuperior
+		if subir {
+			nuevoEstado = enInferior
+		}
+		if sale >= 0 {
+			p.estado[sale], p.d[sale] = nuevoEstado, -theta
+		} else {
+			p.estadoL[^sale], p.dL[^sale] = nuevoEstado, -theta
+		}
+		if entra >= 0 {
+			p.estado[entra], p.d[entra] = basica, 0
+		} else {
+			p.estadoL[^entra], p.dL[^entra] = basica, 0
+		}
+		p.base[r] = entra
+
+		// Actualizar la inversa pivoteando en w[r]
+		pr := p.binv[r]
+		f := 1 / w[r]
+		for k := range pr {
+			pr[k] *= f
+		}
+		for i, b := range p.binv {
+			if i == r || w[i] == 0 {
+				continue
+			}
+			g := w[i]
+			for k, v := range pr {
+				if v != 0 {
+					b[k] -= g * v
+				}
+			}
+		}
+		p.pivotes++
+	}
+	return lpLimite, nil
+}
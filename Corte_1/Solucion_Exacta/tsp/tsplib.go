@@ -84,9 +84,28 @@ var TSPLIBOptimal = map[string]float64{
 	"u2319":     234256,
 	"vm1084":    239297,
 	"vm1748":    336556,
+
+	// ATSP (EDGE_WEIGHT_FORMAT: FULL_MATRIX)
+	"br17":    39,
+	"ftv33":   1286,
+	"ftv35":   1473,
+	"ftv38":   1530,
+	"p43":     5620,
+	"ftv44":   1613,
+	"ftv47":   1776,
+	"ry48p":   14422,
+	"ft53":    6905,
+	"ftv55":   1608,
+	"ftv64":   1839,
+	"ft70":    38673,
+	"ftv70":   1950,
+	"kro124p": 36230,
+	"ftv170":  2755,
 }
 
-// LoadTSPLIB loads a TSP instance from a TSPLIB format file
+// LoadTSPLIB loads a TSP instance from a TSPLIB format file. It supports
+// EUC_2D coordinates and EXPLICIT FULL_MATRIX weights, which is the format
+// of the asymmetric (.atsp) instances.
 func LoadTSPLIB(filepath string) (*Instance, error) {
 	file, err := os.Open(filepath)
 	if err != nil {
@@ -100,6 +119,8 @@ func LoadTSPLIB(filepath string) (*Instance, error) {
 	var name string
 	var dimension int
 	inNodeSection := false
+	inWeightSection := false
+	var weights []float64
 	coords := make(map[int][2]float64)
 
 	for scanner.Scan() {
@@ -119,6 +140,18 @@ func LoadTSPLIB(filepath string) (*Instance, error) {
 			continue
 		}
 
+		if inWeightSection {
+			parts := strings.Fields(line)
+			if _, err := strconv.ParseFloat(parts[0], 64); err == nil {
+				for _, p := range parts {
+					w, _ := strconv.ParseFloat(p, 64)
+					weights = append(weights, w)
+				}
+				continue
+			}
+			inWeightSection = false
+		}
+
 		if strings.HasPrefix(line, "NAME") {
 			parts := strings.SplitN(line, ":", 2)
 			if len(parts) == 2 {
@@ -129,8 +162,15 @@ func LoadTSPLIB(filepath string) (*Instance, error) {
 			if len(parts) == 2 {
 				dimension, _ = strconv.Atoi(strings.TrimSpace(parts[1]))
 			}
+		} else if strings.HasPrefix(line, "EDGE_WEIGHT_FORMAT") {
+			parts := strings.SplitN(line, ":", 2)
+			if len(parts) == 2 && strings.TrimSpace(parts[1]) != "FULL_MATRIX" {
+				return nil, fmt.Errorf("unsupported EDGE_WEIGHT_FORMAT: %s", strings.TrimSpace(parts[1]))
+			}
 		} else if strings.HasPrefix(line, "NODE_COORD_SECTION") {
 			inNodeSection = true
+		} else if strings.HasPrefix(line, "EDGE_WEIGHT_SECTION") {
+			inWeightSection = true
 		}
 	}
 
@@ -141,6 +181,22 @@ func LoadTSPLIB(filepath string) (*Instance, error) {
 	// Build instance
 	inst.NumCities = dimension
 
+	// Explicit weights (row by row, diagonal ignored)
+	if weights != nil {
+		if len(weights) < dimension*dimension {
+			return nil, fmt.Errorf("EDGE_WEIGHT_SECTION has %d weights, expected %d", len(weights), dimension*dimension)
+		}
+		inst.Distance = make([][]float64, dimension)
+		for i := range inst.Distance {
+			inst.Distance[i] = weights[i*dimension : (i+1)*dimension : (i+1)*dimension]
+			inst.Distance[i][i] = 0
+		}
+		if opt, ok := TSPLIBOptimal[name]; ok {
+			inst.OptimalCost = opt
+		}
+		return inst, nil
+	}
+
 	// Convert coords map to slice (TSPLIB uses 1-indexed nodes)
 	coordSlice := make([][2]float64, dimension)
 	for i := 0; i < dimension; i++ {
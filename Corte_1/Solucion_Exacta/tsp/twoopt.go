@@ -0,0 +1,36 @@
+package tsp
+
+// TwoOpt improves a tour with first-improvement 2-opt moves until no move
+// shortens it. The input tour is not modified. Returns the improved tour and
+// its total length.
+func TwoOpt(inst *Instance, tour []int) ([]int, float64) {
+	n := len(tour)
+	t := append([]int(nil), tour...)
+	if n < 4 {
+		return t, inst.TourLength(t)
+	}
+
+	d := inst.Distance
+	improved := true
+	for improved {
+		improved = false
+		for i := 0; i < n-1; i++ {
+			a, b := t[i], t[i+1]
+			for j := i + 2; j < n; j++ {
+				if i == 0 && j == n-1 {
+					continue
+				}
+				c, e := t[j], t[(j+1)%n]
+				if d[a][c]+d[b][e] < d[a][b]+d[c][e]-1e-9 {
+					// Reverse the segment between b and c
+					for l, r := i+1, j; l < r; l, r = l+1, r-1 {
+						t[l], t[r] = t[r], t[l]
+					}
+					b = t[i+1]
+					improved = true
+				}
+			}
+		}
+	}
+	return t, inst.TourLength(t)
+}
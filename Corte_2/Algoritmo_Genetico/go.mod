@@ -1,3 +1,9 @@
 module tsp-ga
 
 go 1.25.6
+
+require heuristica v0.0.0
+replace heuristica => ../../Corte_1/Heuristica
+
+require tsp-common v0.0.0
+replace tsp-common => ../../Corte_2/common
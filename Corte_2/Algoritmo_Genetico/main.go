@@ -3,6 +3,7 @@ package main
 import (
 	"flag"
 	"fmt"
+	"heuristica/tsp"
 	"math/rand"
 	"path/filepath"
 	"time"
@@ -20,11 +21,23 @@ func main() {
 	mut := flag.Float64("mut", 0.3, "Probabilidad de mutacion")
 	tourn := flag.Int("tourn", 3, "Tamaño del torneo para seleccion")
 	stag := flag.Int("stag", 200, "Generaciones sin mejora antes de parar (0 = desactivado)")
+	semilla := flag.String("init", geneticalgorithm.SeedFarthest, "Heuristica constructiva del individuo semilla (farthest, greedy, hilbert, sierpinski, christofides, double-tree)")
+	ventana := flag.Int("ventana", 0, "Tamaño de la ventana de reoptimizacion exacta final (0 = desactivado, 4..16)")
 	flat := flag.Bool("flat", false, "Mostrar informacion en formato plano (sin encabezados)")
 
 	// Parsear los argumentos de la linea de comandos
 	flag.Parse()
 
+	if err := geneticalgorithm.ValidateSeed(*semilla); err != nil {
+		fmt.Printf("ERROR: %v\n", err)
+		return
+	}
+
+	if err := tsp.ValidateWindow(*ventana); err != nil {
+		fmt.Printf("ERROR: %v\n", err)
+		return
+	}
+
 	// Ruta por defecto o por argumento
 	archivo := "../Benchmark/berlin52.tsp"
 	args := flag.Args()
@@ -47,6 +60,7 @@ func main() {
 		MutationRate:    *mut,
 		TournamentSize:  *tourn,
 		StagnationLimit: *stag,
+		Seed:            *semilla,
 	}
 
 	start := time.Now()
@@ -54,6 +68,12 @@ func main() {
 	// 2. Ejecutar Algoritmo Genetico
 	result := solver.GeneticAlgorithmSolver(ciudades, configGA)
 
+	// Post-proceso opcional: reoptimización exacta por ventanas
+	if *ventana > 0 {
+		result.BestTour = tsp.WindowReoptimizeSlice(result.BestTour, utils.DistanciaEuclidiana, *ventana)
+		result.BestCost = utils.CalcularCostoTotal(result.BestTour)
+	}
+
 	elapsed := time.Since(start)
 
 	// 3. Calculo del GAP
@@ -76,8 +96,8 @@ func main() {
 			"Benchmark", "Tiempo", "Costo", "Optimo", "GAP GA (%)")
 		fmt.Printf("%s\t%s\t%.4f\t%.0f\t%.2f\n",
 			nombreArchivo, elapsed, result.BestCost, optimo, gapGA)
-		fmt.Printf("Configuracion GA: Pop=%d, Gen=%d, Mut=%.4f, Tourn=%d, Stag=%d\n",
-			*pop, *gen, *mut, *tourn, *stag)
+		fmt.Printf("Configuracion GA: Pop=%d, Gen=%d, Mut=%.4f, Tourn=%d, Stag=%d, Init=%s, Ventana=%d\n",
+			*pop, *gen, *mut, *tourn, *stag, *semilla, *ventana)
 		fmt.Printf("Convergencia: ultima mejora en gen %d, parada en gen %d por %s\n",
 			result.LastImproveGen, result.TotalGens, result.StopReason)
 	}
@@ -3,14 +3,14 @@ package grasp
 import (
 	"math/rand"
 	"sort"
-	"tsp-sa/models"
-	"tsp-sa/utils"
+	"tsp-common/kdtree"
+	"tsp-common/models"
+	"tsp-common/utils"
 )
 
 type Candidate struct {
-	city  models.City
-	dist  float64
-	index int
+	city int // índice de la ciudad en la matriz de distancias
+	dist float64
 }
 
 type AlphaOption struct {
@@ -20,71 +20,55 @@ type AlphaOption struct {
 	prob    float64
 }
 
-func buildRCL(last models.City, unvisited []models.City, alpha float64) []Candidate {
-	if len(unvisited) == 0 {
+// buildRCL arma la lista restringida de candidatos entre las ciudades vivas
+// del árbol k-d: las que están a distancia <= dmin + alpha*(dmax-dmin) de last.
+// dmin y dmax salen de las consultas de más cercano y más lejano, y la RCL de
+// una consulta por radio, sin recorrer todas las no visitadas.
+func buildRCL(dist utils.MatrizDistancia, arbol *kdtree.Arbol, cities []models.City, last int, alpha float64) []Candidate {
+	cercana, ok := arbol.Cercano(cities[last])
+	if !ok {
 		return nil
 	}
+	lejana, _ := arbol.Lejano(cities[last])
+	minDist, maxDist := dist.Dist(last, cercana), dist.Dist(last, lejana)
+	threshold := minDist + alpha*(maxDist-minDist)
 
-	candidates := make([]Candidate, len(unvisited))
-	minDist, maxDist := 1e18, -1e18
-
-	for i, c := range unvisited {
-		d := utils.DistanciaEuclidiana(last, c)
-		candidates[i] = Candidate{c, d, i}
-		if d < minDist {
-			minDist = d
-		}
-		if d > maxDist {
-			maxDist = d
+	// El radio se agranda apenas para no perder candidatos por redondeo; el
+	// filtro exacto se hace con la matriz
+	var rcl []Candidate
+	for _, c := range arbol.EnRadio(cities[last], threshold*(1+1e-9)) {
+		if d := dist.Dist(last, c); d <= threshold {
+			rcl = append(rcl, Candidate{c, d})
 		}
 	}
 
 	// Ordenar candidatos por distancia para facilitar el sesgo
-	sort.Slice(candidates, func(i, j int) bool {
-		return candidates[i].dist < candidates[j].dist
+	sort.Slice(rcl, func(i, j int) bool {
+		return rcl[i].dist < rcl[j].dist
 	})
-
-	threshold := minDist + alpha*(maxDist-minDist)
-	var rcl []Candidate
-	for _, cand := range candidates {
-		if cand.dist <= threshold {
-			rcl = append(rcl, cand)
-		} else {
-			break
-		}
-	}
 	return rcl
 }
 
-func buildSolution(cities []models.City, alpha float64) []models.City {
+// buildSolution construye un tour greedy aleatorizado. El árbol se restaura
+// al comienzo y cada ciudad visitada se elimina de él.
+func buildSolution(dist utils.MatrizDistancia, arbol *kdtree.Arbol, cities []models.City, alpha float64) []int {
 	n := len(cities)
-	startIdx := rand.Intn(n)
-	tour := []models.City{cities[startIdx]}
+	arbol.Restaurar()
 
-	// Preparar lista de no visitadas excluyendo la inicial
-	unvisited := make([]models.City, 0, n-1)
-	for i, c := range cities {
-		if i != startIdx {
-			unvisited = append(unvisited, c)
-		}
-	}
+	startIdx := rand.Intn(n)
+	tour := make([]int, 1, n)
+	tour[0] = startIdx
+	arbol.Eliminar(startIdx)
 
-	for len(unvisited) > 0 {
+	for arbol.Vivos() > 0 {
 		last := tour[len(tour)-1]
 
-		rcl := buildRCL(last, unvisited, alpha)
+		rcl := buildRCL(dist, arbol, cities, last, alpha)
 
 		selected := chooseWithBias(rcl)
 
 		tour = append(tour, selected.city)
-
-		// Remover la ciudad seleccionada de la lista de no visitadas
-		for i, c := range unvisited {
-			if c.ID == selected.city.ID {
-				unvisited = append(unvisited[:i], unvisited[i+1:]...)
-				break
-			}
-		}
+		arbol.Eliminar(selected.city)
 	}
 	return tour
 }
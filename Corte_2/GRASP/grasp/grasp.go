@@ -2,14 +2,19 @@ package grasp
 
 import (
 	"math/rand"
+	"tsp-common/kdtree"
+	"tsp-common/models"
+	"tsp-common/utils"
 	"tsp-sa/localsearch"
-	"tsp-sa/models"
-	"tsp-sa/utils"
 )
 
-func GraspReactivo(cities []models.City, maxIter int) ([]models.City, float64) {
-	var bestTour []models.City
+// GraspReactivo trabaja con tours de índices sobre la matriz de distancias
+// precalculada de cities. La construcción consulta un árbol k-d de las
+// ciudades, que se arma una sola vez.
+func GraspReactivo(cities []models.City, dist utils.MatrizDistancia, maxIter int) ([]int, float64) {
+	var bestTour []int
 	bestCost := 1e18
+	arbol := kdtree.Nuevo(cities)
 
 	// Inicializar opciones de alpha
 	alphas := []*AlphaOption{
@@ -22,17 +27,17 @@ func GraspReactivo(cities []models.City, maxIter int) ([]models.City, float64) {
 		alphaOpt := alphas[alphaIdx]
 
 		// Construccion con punto de inicio aleatorio
-		initialSolution := buildSolution(cities, alphaOpt.value)
+		initialSolution := buildSolution(dist, arbol, cities, alphaOpt.value)
 
 		// Busqueda local
-		refinedTour, refinedCost := localsearch.TwoOpt(initialSolution)
+		refinedTour, refinedCost := localsearch.TwoOpt(initialSolution, dist)
 
 		alphaOpt.costSum += refinedCost
 		alphaOpt.uses++
 
 		if refinedCost < bestCost {
 			bestCost = refinedCost
-			bestTour = utils.CopiarTour(refinedTour)
+			bestTour = utils.CopiarPermutacion(refinedTour)
 		}
 	}
 
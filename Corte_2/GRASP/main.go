@@ -3,19 +3,29 @@ package main
 import (
 	"flag"
 	"fmt"
+	"heuristica/tsp"
+	"math/rand"
 	"os"
 	"text/tabwriter"
 	"time"
-	"math/rand"
-	"tsp-sa/parser"
+	"tsp-common/utils"
 	"tsp-sa/grasp"
-	"tsp-sa/utils"
+	"tsp-sa/parser"
 )
 
 func main() {
 	// Configuracion inicial y semilla de aleatoriedad
 	rand.Seed(time.Now().UnixNano())
-	file := "../Benchmark/berlin52.tsp" 
+
+	ventana := flag.Int("ventana", 0, "Tamaño de la ventana de reoptimizacion exacta final (0 = desactivado, 4..16)")
+	flag.Parse()
+
+	if err := tsp.ValidateWindow(*ventana); err != nil {
+		fmt.Printf("ERROR: %v\n", err)
+		return
+	}
+
+	file := "../Benchmark/berlin52.tsp"
 	args := flag.Args()
 	if len(args) > 0 {
 		file = args[0]
@@ -34,7 +44,14 @@ func main() {
 
 	// GraspReactivo coordinara la construccion, el sesgo, el inicio aleatorio y el 2-opt
 	start := time.Now()
-	bestTour, bestCost := grasp.GraspReactivo(cities, 1000)
+	dist := utils.NuevaMatrizDistancia(cities)
+	bestTour, bestCost := grasp.GraspReactivo(cities, dist, 1000)
+
+	// Post-proceso opcional: reoptimización exacta por ventanas
+	if *ventana > 0 {
+		bestTour = tsp.WindowReoptimizeFunc(bestTour, dist.Dist, *ventana)
+		bestCost = dist.CostoPermutacion(bestTour)
+	}
 	elapsed := time.Since(start)
 
 	// CALCULO DEL GAP
@@ -54,13 +71,13 @@ func printTable(name string, tiempo time.Duration, result float64, optimo float6
 	w := tabwriter.NewWriter(os.Stdout, 8, 0, 2, ' ', tabwriter.Debug)
 
 	fmt.Fprintln(w, "Instancia\tTiempo\tResultado\tOptimo\tGAP (%)")
-	
-	line := fmt.Sprintf("%s\t%v\t%.2f\t%.2f\t%.2f", 
-		name, 
+
+	line := fmt.Sprintf("%s\t%v\t%.2f\t%.2f\t%.2f",
+		name,
 		tiempo.Round(time.Millisecond), // Redondear tiempo para limpieza (quitar de ser necesario)
 		result, optimo, gap)
-	
+
 	fmt.Fprintln(w, line)
-	
+
 	w.Flush()
-}
\ No newline at end of file
+}
@@ -1,8 +0,0 @@
-package models
-
-// Estructura de ciudad
-type City struct {
-	ID int
-	X  float64
-	Y  float64
-}
@@ -5,7 +5,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
-	"tsp-sa/models"
+	"tsp-common/models"
 )
 
 // Funcion para leer el archivo TSP
@@ -4,3 +4,6 @@ go 1.25.6
 
 require tsp-common v0.0.0
 replace tsp-common => ../common
+
+require heuristica v0.0.0
+replace heuristica => ../../Corte_1/Heuristica
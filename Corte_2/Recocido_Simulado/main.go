@@ -3,6 +3,7 @@ package main
 import (
 	"flag"
 	"fmt"
+	"heuristica/tsp"
 	"math/rand"
 	"path/filepath"
 	"time"
@@ -19,11 +20,23 @@ func main() {
 	alpha := flag.Float64("alpha", 0.995, "Factor de enfriamiento (Alpha)")
 	minTemp := flag.Float64("min_temp", 0.001, "Temperatura mínima de parada")
 	iterPerTemp := flag.Int("iter", 1000, "Iteraciones por nivel de temperatura")
+	inicial := flag.String("init", tsp.InitialRandom, "Tour inicial de la busqueda local (random, hilbert, sierpinski)")
+	ventana := flag.Int("ventana", 0, "Tamaño de la ventana de reoptimizacion exacta final (0 = desactivado, 4..16)")
 	flat := flag.Bool("flat", false, "Mostrar informacion en formato plano (sin encabezados)")
 	
 	// Parsear los argumentos de la línea de comandos
 	flag.Parse()
 
+	if err := tsp.ValidateInitial(*inicial); err != nil {
+		fmt.Printf("ERROR: %v\n", err)
+		return
+	}
+
+	if err := tsp.ValidateWindow(*ventana); err != nil {
+		fmt.Printf("ERROR: %v\n", err)
+		return
+	}
+
 	// Ruta por defecto o por argumento
 	archivo := "../Benchmark/berlin52.tsp" 
 	args := flag.Args()
@@ -48,9 +61,18 @@ func main() {
 	}
 	start := time.Now()
 
+	// Matriz de distancias compartida por la búsqueda local y el recocido
+	dist := utils.NuevaMatrizDistancia(ciudades)
+
 	// Ejecutar Algoritmo
-	mejorTourLS, mejorCostoLS := solver.LocalSearch(ciudades)
-	mejorTourSA, mejorCostoSA := solver.SimulatedAnnealingSolver(mejorTourLS, mejorCostoLS, configSA)
+	mejorTourLS, mejorCostoLS := solver.LocalSearch(dist, ciudades, *inicial)
+	mejorTourSA, mejorCostoSA := solver.SimulatedAnnealingSolver(mejorTourLS, mejorCostoLS, dist, configSA)
+
+	// Post-proceso opcional: reoptimización exacta por ventanas
+	if *ventana > 0 {
+		mejorTourSA = tsp.WindowReoptimizeFunc(mejorTourSA, dist.Dist, *ventana)
+		mejorCostoSA = dist.CostoPermutacion(mejorTourSA)
+	}
 	
 	elapsed := time.Since(start)
 
@@ -74,8 +96,8 @@ func main() {
 	} else {
 		fmt.Printf("%-10s\t%-10s\t%-10s\t%-6s\t%-10s\n", "Benchmark", "Tiempo", "Costo", "Optimo", "GAP SA (%)")
 		fmt.Printf("%s\t%s\t%.4f\t%.0f\t%.2f\n", nombreArchivo,elapsed, mejorCostoSA, optimo, gapSA)
-		fmt.Printf("Configuración SA: Temp=%.2f, Alpha=%.4f, Min=%.4f, Iter=%d\n", 
-		*initialTemp, *alpha, *minTemp, *iterPerTemp)	
+		fmt.Printf("Configuración SA: Temp=%.2f, Alpha=%.4f, Min=%.4f, Iter=%d, Init=%s, Ventana=%d\n", 
+		*initialTemp, *alpha, *minTemp, *iterPerTemp, *inicial, *ventana)	
 	}
 
 }
\ No newline at end of file
@@ -3,7 +3,7 @@ package simulatedannealing
 import (
 	"math"
 	"math/rand"
-	"tsp-common/models"
+	"tsp-common/moves"
 	"tsp-common/utils"
 )
 
@@ -15,14 +15,15 @@ type SAConfig struct {
 	IterPerTemp int     // Iteraciones por cada nivel de temperatura (Equilibrio térmico)
 }
 
-// EjecutarSA aplica Recocido Simulado sobre un tour existente
-func EjecutarSA(tourInicial []models.City, config SAConfig) ([]models.City, float64) {
+// EjecutarSA aplica Recocido Simulado sobre un tour existente (permutación de
+// índices) usando la matriz de distancias precalculada.
+func EjecutarSA(tourInicial []int, dist utils.MatrizDistancia, config SAConfig) ([]int, float64) {
 	
 	// Inicialización
-	tourActual := utils.CopiarTour(tourInicial)
-	costoActual := utils.CalcularCostoTotal(tourActual)
+	tourActual := utils.CopiarPermutacion(tourInicial)
+	costoActual := dist.CostoPermutacion(tourActual)
 
-	mejorTour := utils.CopiarTour(tourActual)
+	mejorTour := utils.CopiarPermutacion(tourActual)
 	mejorCosto := costoActual
 
 	tempActual := config.InitialTemp
@@ -46,16 +47,8 @@ func EjecutarSA(tourInicial []models.City, config SAConfig) ([]models.City, floa
 			}
 
 			// B. Calcular Delta E (Cambio de costo)
-			idxPrevI := (i - 1 + n) % n
-			idxNextJ := (j + 1) % n
-
-			dEliminada1 := utils.DistanciaEuclidiana(tourActual[idxPrevI], tourActual[i])
-			dEliminada2 := utils.DistanciaEuclidiana(tourActual[j], tourActual[idxNextJ])
-			
-			dNueva1 := utils.DistanciaEuclidiana(tourActual[idxPrevI], tourActual[j])
-			dNueva2 := utils.DistanciaEuclidiana(tourActual[i], tourActual[idxNextJ])
-
-			delta := (dNueva1 + dNueva2) - (dEliminada1 + dEliminada2)
+			mov := moves.TwoOpt{I: i, J: j}
+			delta := mov.Delta(tourActual, dist.Dist)
 
 			// Criterio de Aceptación (Metropolis)
 			aceptar := false
@@ -70,12 +63,12 @@ func EjecutarSA(tourInicial []models.City, config SAConfig) ([]models.City, floa
 
 			// Aplicar cambio si es aceptado
 			if aceptar {
-				invertirSegmento(tourActual, i, j)
+				mov.Apply(tourActual)
 				costoActual += delta
 
 				if costoActual < mejorCosto {
 					mejorCosto = costoActual
-					mejorTour = utils.CopiarTour(tourActual)
+					mejorTour = utils.CopiarPermutacion(tourActual)
 				}
 			}
 		}
@@ -86,12 +79,3 @@ func EjecutarSA(tourInicial []models.City, config SAConfig) ([]models.City, floa
 
 	return mejorTour, mejorCosto
 }
-
-// Función auxiliar para invertir segmento (igual que en 2-opt, pero local)
-func invertirSegmento(tour []models.City, i, j int) {
-	for i < j {
-		tour[i], tour[j] = tour[j], tour[i]
-		i++
-		j--
-	}
-}
\ No newline at end of file
@@ -1,29 +1,29 @@
 package solver
 
 import (
-	"math/rand"
+	"heuristica/kopt"
+	"heuristica/tsp"
 	"tsp-common/models"
 	"tsp-common/utils"
-	"tsp-sa/localsearch"
 )
 
 // LocalSearch ejecuta el algoritmo de Búsqueda
-// Genera un inicio aleatorio y aplica 2-opt hasta llegar a un óptimo local.
-func LocalSearch(ciudades []models.City) ([]models.City, float64) {
-	
-	// Solución Inicial Aleatoria
-	tourActual := utils.CopiarTour(ciudades)
-	
-	// Aleatorizamos el orden (Random Start)
-	rand.Shuffle(len(tourActual), func(i, j int) {
-		tourActual[i], tourActual[j] = tourActual[j], tourActual[i]
-	})
+// Parte del tour inicial indicado (ver tsp.InitialTour) y aplica el 2-opt de
+// heuristica/kopt hasta llegar a un óptimo local; por encima de
+// utils.MaxCiudadesMatriz ciudades usa listas de vecinos, así que también
+// termina en segundos con 100k+ ciudades. El tour es una permutación de
+// índices sobre las ciudades de la matriz.
+func LocalSearch(dist utils.MatrizDistancia, ciudades []models.City, inicial string) ([]int, float64) {
+	coords := utils.Coordenadas(ciudades)
 
-	//costoInicial := utils.CalcularCostoTotal(tourActual)
+	// Solución Inicial
+	tourActual := tsp.InitialTour(coords, inicial)
+
+	//costoInicial := dist.CostoPermutacion(tourActual)
 	//fmt.Printf("   >> Costo Inicial (Aleatorio): %.4f\n", costoInicial)
 
 	// Aplicar 2-Opt
-	mejorTour, mejorCosto := localsearch.TwoOpt(tourActual)
+	mejorTour, mejorCosto := kopt.NuevaBusquedaLocal(kopt.Metodo2Opt, coords, dist.Dist).Optimizar(tourActual)
 
 	return mejorTour, mejorCosto
 }
@@ -1,16 +1,16 @@
 package solver
 
 import (
-	"tsp-common/models"
+	"tsp-common/utils"
 	"tsp-sa/simulatedannealing"
 )
 
 // SimulatedAnnealingSolver recibe un tour inicial (que puede venir de Local Search)
 // y lo mejora usando Recocido Simulado.
-func SimulatedAnnealingSolver(tourInicial []models.City, costoInicial float64, config simulatedannealing.SAConfig) ([]models.City, float64) {
+func SimulatedAnnealingSolver(tourInicial []int, costoInicial float64, dist utils.MatrizDistancia, config simulatedannealing.SAConfig) ([]int, float64) {
 	
 	// Ejecutar SA
-	mejorTour, mejorCosto := simulatedannealing.EjecutarSA(tourInicial, config)
+	mejorTour, mejorCosto := simulatedannealing.EjecutarSA(tourInicial, dist, config)
 
 	return mejorTour, mejorCosto
 }
\ No newline at end of file
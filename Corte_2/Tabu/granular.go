@@ -0,0 +1,320 @@
+package main
+
+import (
+	"math"
+	"tsp-common/kdtree"
+	"tsp-common/models"
+	"tsp-common/moves"
+	"tsp-common/utils"
+)
+
+// Configuración de parámetros para la búsqueda tabú granular
+type GranularConfig struct {
+	MaxIteraciones int
+	Tenencia       int
+	Candidatos     int // Vecinos más cercanos por ciudad que forman la vecindad
+}
+
+// movGranular es un movimiento 2-opt de la vecindad granular: agrega la
+// arista corta (a, c), con c entre los candidatos de a, y quita las aristas
+// que salen de a y de c en la misma dirección (dir 0 = siguiente, 1 = anterior)
+type movGranular struct {
+	a, c  int
+	dir   int
+	delta float64
+}
+
+// granular es el estado de TabuGranular
+type granular struct {
+	n        int
+	dist     moves.Distancia
+	tour     []int
+	pos      []int
+	cand     [][]int
+	candInv  [][]int // candInv[c] = ciudades que tienen a c como candidato
+	tenencia int
+
+	// Memoria tabú sobre aristas: no volver a agregar las quitadas ni quitar
+	// las agregadas hasta la iteración guardada
+	tabuAgregar  map[uint64]int
+	tabuQuitar   map[uint64]int
+	vencimientos map[int][]uint64
+
+	// Mejor movimiento libre y mejor tabú de cada ciudad; solo se recalculan
+	// las ciudades marcadas como sucias
+	libre, tabu []movGranular
+	sucio       []bool
+	sucios      []int
+}
+
+// TabuGranular es la búsqueda tabú granular (Toth y Vigo, 2003) sobre 2-opt.
+//
+// La vecindad se restringe a los movimientos que agregan una arista entre
+// una ciudad y uno de sus Candidatos vecinos más cercanos, así que tiene
+// O(n k) movimientos en lugar de O(n^2). Los atributos tabú son las aristas:
+// las que un movimiento quita no se pueden volver a agregar y las que agrega
+// no se pueden quitar durante Tenencia iteraciones, salvo por aspiración.
+//
+// La evaluación es incremental: cada ciudad guarda su mejor movimiento libre
+// y su mejor movimiento tabú, y tras cada iteración solo se recalculan las
+// ciudades cuyas aristas, orientación o estado tabú cambiaron. Las
+// distancias se piden a dist, así que no hace falta la matriz completa; el
+// tour inicial es el vecino más cercano construido con el árbol k-d.
+func TabuGranular(ciudades []models.City, dist moves.Distancia, config GranularConfig) ([]int, float64) {
+	n := len(ciudades)
+	g := &granular{
+		n:            n,
+		dist:         dist,
+		tour:         tourVecinoCercano(ciudades),
+		pos:          make([]int, n),
+		tenencia:     config.Tenencia,
+		tabuAgregar:  make(map[uint64]int),
+		tabuQuitar:   make(map[uint64]int),
+		vencimientos: make(map[int][]uint64),
+		libre:        make([]movGranular, n),
+		tabu:         make([]movGranular, n),
+		sucio:        make([]bool, n),
+	}
+	for i, c := range g.tour {
+		g.pos[c] = i
+	}
+	costoActual := costoTour(g.tour, dist)
+	tourBest := utils.CopiarPermutacion(g.tour)
+	costoBest := costoActual
+	if n < 5 {
+		return tourBest, costoBest
+	}
+	g.construirCandidatos(ciudades, config.Candidatos)
+	for c := 0; c < n; c++ {
+		g.marcar(c)
+	}
+
+	for iter := 1; iter <= config.MaxIteraciones; iter++ {
+
+		// 1. Aristas cuyo estado tabú vence en esta iteración
+		for _, e := range g.vencimientos[iter] {
+			u, v := int(e>>32), int(e&math.MaxUint32)
+			if g.tabuAgregar[e] == iter {
+				delete(g.tabuAgregar, e)
+				g.marcarArista(u, v)
+			}
+			if g.tabuQuitar[e] == iter {
+				delete(g.tabuQuitar, e)
+				g.marcarArista(u, v)
+			}
+		}
+		delete(g.vencimientos, iter)
+
+		// 2. Recalcular solo las ciudades sucias
+		for _, a := range g.sucios {
+			g.evaluar(a, iter)
+			g.sucio[a] = false
+		}
+		g.sucios = g.sucios[:0]
+
+		// 3. Mejor movimiento admisible: libre, o tabú con aspiración
+		mejor := movGranular{delta: math.Inf(1)}
+		for a := 0; a < n; a++ {
+			if m := g.libre[a]; m.delta < mejor.delta {
+				mejor = m
+			}
+			if m := g.tabu[a]; m.delta < mejor.delta && costoActual+m.delta < costoBest-1e-9 {
+				mejor = m
+			}
+		}
+		if math.IsInf(mejor.delta, 1) {
+			continue
+		}
+
+		// 4. Moverse a la siguiente solución
+		g.aplicar(mejor, iter)
+		costoActual += mejor.delta
+		if costoActual < costoBest-1e-9 {
+			tourBest = utils.CopiarPermutacion(g.tour)
+			costoBest = costoActual
+		}
+	}
+
+	return tourBest, costoTour(tourBest, dist)
+}
+
+// tourVecinoCercano construye el tour del vecino más cercano desde la ciudad
+// 0 en O(n log n) esperado usando el árbol k-d
+func tourVecinoCercano(ciudades []models.City) []int {
+	n := len(ciudades)
+	tour := make([]int, 0, n)
+	if n == 0 {
+		return tour
+	}
+	arbol := kdtree.Nuevo(ciudades)
+	actual := 0
+	for {
+		tour = append(tour, actual)
+		arbol.Eliminar(actual)
+		siguiente, ok := arbol.Cercano(ciudades[actual])
+		if !ok {
+			return tour
+		}
+		actual = siguiente
+	}
+}
+
+// costoTour suma las aristas de la permutación con la función de distancia
+func costoTour(tour []int, dist moves.Distancia) float64 {
+	total := 0.0
+	for i := 0; i < len(tour)-1; i++ {
+		total += dist(tour[i], tour[i+1])
+	}
+	if len(tour) > 0 {
+		total += dist(tour[len(tour)-1], tour[0])
+	}
+	return total
+}
+
+// construirCandidatos arma las listas de los k vecinos más cercanos de cada
+// ciudad con el árbol k-d y sus listas inversas
+func (g *granular) construirCandidatos(ciudades []models.City, k int) {
+	k = min(max(k, 1), g.n-1)
+	arbol := kdtree.Nuevo(ciudades)
+	g.cand = make([][]int, g.n)
+	g.candInv = make([][]int, g.n)
+	for a := range ciudades {
+		lista := make([]int, 0, k)
+		for _, c := range arbol.KCercanos(ciudades[a], k+1) {
+			if c != a && len(lista) < k {
+				lista = append(lista, c)
+			}
+		}
+		g.cand[a] = lista
+		for _, c := range lista {
+			g.candInv[c] = append(g.candInv[c], a)
+		}
+	}
+}
+
+// vecino devuelve la ciudad siguiente (dir 0) o anterior (dir 1) a c
+func (g *granular) vecino(c, dir int) int {
+	if dir == 0 {
+		return g.tour[(g.pos[c]+1)%g.n]
+	}
+	return g.tour[(g.pos[c]-1+g.n)%g.n]
+}
+
+// arista devuelve la clave de la arista sin orden {a, b}
+func arista(a, b int) uint64 {
+	if a > b {
+		a, b = b, a
+	}
+	return uint64(a)<<32 | uint64(b)
+}
+
+// esTabu indica si quitar (a, a2) y (c, c2) y agregar (a, c) y (a2, c2) toca
+// alguna arista tabú en la iteración iter
+func (g *granular) esTabu(a, a2, c, c2, iter int) bool {
+	return g.tabuAgregar[arista(a, c)] > iter || g.tabuAgregar[arista(a2, c2)] > iter ||
+		g.tabuQuitar[arista(a, a2)] > iter || g.tabuQuitar[arista(c, c2)] > iter
+}
+
+// evaluar recalcula el mejor movimiento libre y el mejor tabú de la ciudad a
+func (g *granular) evaluar(a, iter int) {
+	libre, tabu := movGranular{delta: math.Inf(1)}, movGranular{delta: math.Inf(1)}
+	for dir := 0; dir < 2; dir++ {
+		a2 := g.vecino(a, dir)
+		da := g.dist(a, a2)
+		for _, c := range g.cand[a] {
+			if c == a2 {
+				continue
+			}
+			c2 := g.vecino(c, dir)
+			if c2 == a {
+				continue
+			}
+			delta := g.dist(a, c) + g.dist(a2, c2) - da - g.dist(c, c2)
+			m := movGranular{a: a, c: c, dir: dir, delta: delta}
+			if g.esTabu(a, a2, c, c2, iter) {
+				if delta < tabu.delta {
+					tabu = m
+				}
+			} else if delta < libre.delta {
+				libre = m
+			}
+		}
+	}
+	g.libre[a], g.tabu[a] = libre, tabu
+}
+
+// aplicar realiza el movimiento, registra sus aristas como tabú y marca las
+// ciudades afectadas
+func (g *granular) aplicar(m movGranular, iter int) {
+	a, c := m.a, m.c
+	a2, c2 := g.vecino(a, m.dir), g.vecino(c, m.dir)
+
+	// Con dir 0 el tour es a a2 ... c c2 y se invierte a2..c; con dir 1 es
+	// c2 c ... a2 a y se invierte c..a2
+	if m.dir == 0 {
+		g.invertir(g.pos[a2], g.pos[c])
+	} else {
+		g.invertir(g.pos[c], g.pos[a2])
+	}
+
+	vence := iter + g.tenencia
+	for _, e := range []uint64{arista(a, a2), arista(c, c2)} {
+		g.tabuAgregar[e] = vence
+		g.vencimientos[vence] = append(g.vencimientos[vence], e)
+	}
+	for _, e := range []uint64{arista(a, c), arista(a2, c2)} {
+		g.tabuQuitar[e] = vence
+		g.vencimientos[vence] = append(g.vencimientos[vence], e)
+	}
+
+	g.marcarArista(a, a2)
+	g.marcarArista(c, c2)
+}
+
+// invertir invierte el tramo circular de posiciones i..j; si es más largo que
+// medio tour invierte el complemento, que deja el mismo ciclo. Las ciudades
+// invertidas cambian de orientación, así que se marcan junto con quienes las
+// tienen como candidato.
+func (g *granular) invertir(i, j int) {
+	largo := (j-i+g.n)%g.n + 1
+	if 2*largo > g.n {
+		i, j = (j+1)%g.n, (i-1+g.n)%g.n
+		largo = g.n - largo
+	}
+	for k := 0; k < largo; k++ {
+		g.marcarCiudad(g.tour[(i+k)%g.n])
+	}
+	for ; largo > 1; largo -= 2 {
+		ci, cj := g.tour[i], g.tour[j]
+		g.tour[i], g.tour[j] = cj, ci
+		g.pos[cj], g.pos[ci] = i, j
+		i, j = (i+1)%g.n, (j-1+g.n)%g.n
+	}
+}
+
+// marcar agrega la ciudad a la lista de sucias
+func (g *granular) marcar(c int) {
+	if !g.sucio[c] {
+		g.sucio[c] = true
+		g.sucios = append(g.sucios, c)
+	}
+}
+
+// marcarCiudad marca c y las ciudades que la tienen como candidato
+func (g *granular) marcarCiudad(c int) {
+	g.marcar(c)
+	for _, a := range g.candInv[c] {
+		g.marcar(a)
+	}
+}
+
+// marcarArista marca las ciudades cuyos movimientos pueden agregar o quitar
+// la arista {u, v}: sus extremos, los vecinos de ambos en el tour y quienes
+// los tienen como candidato
+func (g *granular) marcarArista(u, v int) {
+	for _, c := range []int{u, v} {
+		g.marcarCiudad(c)
+		g.marcar(g.vecino(c, 0))
+		g.marcar(g.vecino(c, 1))
+	}
+}
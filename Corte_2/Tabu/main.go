@@ -3,6 +3,7 @@ package main
 import (
 	"flag"
 	"fmt"
+	"heuristica/tsp"
 	"math/rand"
 	"path/filepath"
 	"time"
@@ -14,12 +15,38 @@ func main() {
 	rand.Seed(time.Now().UnixNano())
 
 	maxIter := flag.Int("iter", 2000, "Máximo de iteraciones")
-	tenencia := flag.Int("tenure", 25, "Tenencia Tabú")
+	tenencia := flag.Int("tenure", 25, "Tenencia Tabú (inicial en la variante reactiva)")
+	variante := flag.String("variant", VarianteFija, "Variante tabú: fixed (tenencia fija), reactive (tenencia adaptativa y escape) o granular (lista de candidatos, escala a miles de ciudades)")
+	movimientos := flag.String("moves", Vecindario2Opt, "Vecindarios combinados, separados por coma: 2opt, swap, insert, oropt (fixed)")
+	candidatos := flag.Int("cand", 10, "Vecinos más cercanos por ciudad en la vecindad (granular)")
+	faseDiv := flag.Int("div_iter", 100, "Iteraciones de diversificación por frecuencia tras cada escape (reactive)")
+	penalizacion := flag.Float64("penalty", 0.3, "Peso de la penalización por frecuencia de aristas (reactive)")
+	ventana := flag.Int("ventana", 0, "Tamaño de la ventana de reoptimizacion exacta final (0 = desactivado, 4..16)")
 	flat := flag.Bool("flat", false, "Mostrar informacion en formato plano (sin encabezados)")
 
 	// Parsear los argumentos de la línea de comandos
 	flag.Parse()
 
+	if err := tsp.ValidateWindow(*ventana); err != nil {
+		fmt.Printf("ERROR: %v\n", err)
+		return
+	}
+
+	if err := ValidarVariante(*variante); err != nil {
+		fmt.Printf("ERROR: %v\n", err)
+		return
+	}
+
+	vecindarios, err := ParsearVecindarios(*movimientos)
+	if err != nil {
+		fmt.Printf("ERROR: %v\n", err)
+		return
+	}
+	if *variante != VarianteFija && *movimientos != Vecindario2Opt {
+		fmt.Printf("ERROR: -moves solo aplica a la variante %s\n", VarianteFija)
+		return
+	}
+
 	// Ruta por defecto o por argumento
 	archivo := "../Benchmark/berlin52.tsp"
 	args := flag.Args()
@@ -37,8 +64,46 @@ func main() {
 
 	start := time.Now()
 
-	// Ejecutar Algoritmo
-	mejorTour, mejorCosto := TabuSearch(ciudades, *maxIter, *tenencia)
+	var mejorTour []int
+	var mejorCosto float64
+	var reactiva ReactivaResultado
+	var uso []UsoVecindario
+	if *variante == VarianteGranular {
+		// Por encima de utils.MaxCiudadesMatriz la matriz no se precalcula y
+		// las distancias salen de las coordenadas
+		distCiudades := utils.NuevaMatrizDistancia(ciudades).Dist
+		mejorTour, mejorCosto = TabuGranular(ciudades, distCiudades, GranularConfig{
+			MaxIteraciones: *maxIter,
+			Tenencia:       *tenencia,
+			Candidatos:     *candidatos,
+		})
+
+		// Post-proceso opcional: reoptimización exacta por ventanas
+		if *ventana > 0 {
+			mejorTour = tsp.WindowReoptimizeFunc(mejorTour, distCiudades, *ventana)
+			mejorCosto = costoTour(mejorTour, distCiudades)
+		}
+	} else {
+		// Ejecutar Algoritmo sobre la matriz de distancias precalculada
+		dist := utils.NuevaMatrizDistancia(ciudades)
+		if *variante == VarianteReactiva {
+			reactiva = TabuReactiva(dist, len(ciudades), ReactivaConfig{
+				MaxIteraciones:      *maxIter,
+				TenenciaInicial:     *tenencia,
+				FaseDiversificacion: *faseDiv,
+				Penalizacion:        *penalizacion,
+			})
+			mejorTour, mejorCosto = reactiva.Tour, reactiva.Costo
+		} else {
+			mejorTour, mejorCosto, uso = TabuSearch(dist, len(ciudades), *maxIter, *tenencia, vecindarios)
+		}
+
+		// Post-proceso opcional: reoptimización exacta por ventanas
+		if *ventana > 0 {
+			mejorTour = tsp.WindowReoptimizeFunc(mejorTour, dist.Dist, *ventana)
+			mejorCosto = dist.CostoPermutacion(mejorTour)
+		}
+	}
 
 	elapsed := time.Since(start)
 
@@ -60,6 +125,13 @@ func main() {
 	} else {
 		fmt.Printf("%-10s\t%-10s\t%-10s\t%-6s\t%-10s\n", "Benchmark", "Tiempo", "Costo", "Optimo", "GAP Tabu (%)")
 		fmt.Printf("%s\t%s\t%.4f\t%.0f\t%.2f\n", nombreArchivo, elapsed, mejorCosto, optimo, gapTabu)
-		fmt.Printf("Configuración Tabu: Iter=%d, Tenure=%d\n", *maxIter, *tenencia)
+		fmt.Printf("Configuración Tabu: Variante=%s, Iter=%d, Tenure=%d, Ventana=%d\n", *variante, *maxIter, *tenencia, *ventana)
+		if *variante == VarianteReactiva {
+			fmt.Printf("Reactiva: Repeticiones=%d, Escapes=%d, Tenure final=%d, Tenure max=%d\n",
+				reactiva.Repeticiones, reactiva.Escapes, reactiva.TenenciaFinal, reactiva.TenenciaMax)
+		}
+		for _, u := range uso {
+			fmt.Printf("Movimiento %s: Evaluados=%d, Aplicados=%d, Mejoras=%d\n", u.Nombre, u.Evaluados, u.Aplicados, u.Mejoras)
+		}
 	}
 }
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"tsp-common/moves"
+	"tsp-common/utils"
+)
+
+// Variantes de búsqueda tabú seleccionables con la opción -variant.
+const (
+	VarianteFija     = "fixed"    // Tenencia fija (TabuSearch)
+	VarianteReactiva = "reactive" // Tenencia adaptativa con detección de ciclos (TabuReactiva)
+	VarianteGranular = "granular" // Vecindad de candidatos y atributos sobre aristas (TabuGranular)
+)
+
+// Variantes lista las variantes disponibles.
+var Variantes = []string{VarianteFija, VarianteReactiva, VarianteGranular}
+
+// ValidarVariante devuelve un error si el nombre no corresponde a ninguna variante.
+func ValidarVariante(variante string) error {
+	for _, v := range Variantes {
+		if v == variante {
+			return nil
+		}
+	}
+	return fmt.Errorf("variante tabú desconocida %q (opciones: %v)", variante, Variantes)
+}
+
+// Parámetros de la reacción (Battiti y Tecchiolli, 1994)
+const (
+	IncrementoTenencia = 1.1 // Factor de la tenencia al repetirse una solución
+	DecrementoTenencia = 0.9 // Factor de la tenencia tras un ciclo medio sin repeticiones
+	RepeticionesCaos   = 3   // Visitas a partir de las cuales una solución es caótica
+	SolucionesCaos     = 3   // Soluciones caóticas que disparan el escape
+)
+
+// Configuración de parámetros para la búsqueda tabú reactiva
+type ReactivaConfig struct {
+	MaxIteraciones      int
+	TenenciaInicial     int
+	FaseDiversificacion int     // Iteraciones penalizadas por frecuencia después de cada escape
+	Penalizacion        float64 // Peso de la penalización, en aristas medias por uso relativo
+}
+
+// Resultado de la búsqueda tabú reactiva
+type ReactivaResultado struct {
+	Tour          []int
+	Costo         float64
+	Repeticiones  int // Soluciones revisitadas
+	Escapes       int
+	TenenciaFinal int
+	TenenciaMax   int
+}
+
+// visita es lo que la memoria de soluciones guarda por hash
+type visita struct {
+	costo float64
+	iter  int
+	veces int
+}
+
+// TabuReactiva es la búsqueda tabú reactiva sobre la vecindad 2-opt.
+//
+// Cada solución visitada se guarda por un hash de sus aristas que se
+// actualiza en O(1) con cada movimiento. Si se vuelve a una solución, la
+// tenencia se multiplica por IncrementoTenencia (y sube al menos en 1), hasta
+// n/2; si pasa un ciclo medio sin repeticiones, por DecrementoTenencia.
+// Cuando SolucionesCaos soluciones se repitieron RepeticionesCaos veces la
+// búsqueda está atrapada y escapa con una serie de movimientos aleatorios.
+// Tras cada escape, durante FaseDiversificacion iteraciones, los movimientos
+// se evalúan con una penalización proporcional a cuántas veces se agregaron
+// antes sus aristas (memoria de largo plazo), lo que empuja hacia aristas poco
+// usadas. Ambas memorias son mapas con solo los atributos vigentes y las
+// aristas agregadas alguna vez, no matrices n×n.
+func TabuReactiva(dist utils.MatrizDistancia, n int, config ReactivaConfig) ReactivaResultado {
+
+	// 1. Solución Inicial (Aleatoria)
+	tourActual := utils.PermutacionIdentidad(n)
+	rand.Shuffle(len(tourActual), func(i, j int) {
+		tourActual[i], tourActual[j] = tourActual[j], tourActual[i]
+	})
+	costoActual := dist.CostoPermutacion(tourActual)
+
+	res := ReactivaResultado{
+		Tour:  utils.CopiarPermutacion(tourActual),
+		Costo: costoActual,
+	}
+	if n < 4 {
+		res.TenenciaFinal = config.TenenciaInicial
+		res.TenenciaMax = config.TenenciaInicial
+		return res
+	}
+
+	// 2. Memorias: tabú (corto plazo, solo los atributos vigentes, como en
+	// TabuSearch), frecuencia de las aristas agregadas (largo plazo) y
+	// soluciones visitadas
+	tabu := make(map[moves.Atributo]int)
+	vencimientos := make(map[int][]moves.Atributo)
+	frecuencia := make(map[uint64]int)
+	visitadas := make(map[uint64]visita)
+
+	claves := make([]uint64, n)
+	for i := range claves {
+		claves[i] = rand.Uint64() | 1
+	}
+	// clave de la arista sin orden {a, b}
+	clave := func(a, b int) uint64 { return claves[a] * claves[b] }
+	hash := uint64(0)
+	for i := range tourActual {
+		hash += clave(tourActual[i], tourActual[(i+1)%n])
+	}
+
+	aristaMedia := costoActual / float64(n)
+	tenenciaMax := float64(max(n/2, 1))
+	tenencia := math.Min(math.Max(float64(config.TenenciaInicial), 1), tenenciaMax)
+	cicloMedio := 1.0
+	ultimoCambio := 0
+	caoticas := 0
+	finDiversificacion := 0
+	res.TenenciaMax = int(math.Round(tenencia))
+
+	// mover aplica m sobre el tour actual actualizando hash y frecuencias
+	mover := func(m moves.TwoOpt, delta float64) {
+		a, b := tourActual[m.I-1], tourActual[m.I]
+		c, d := tourActual[m.J], tourActual[(m.J+1)%n]
+		hash += clave(a, c) + clave(b, d) - clave(a, b) - clave(c, d)
+		frecuencia[arista(a, c)]++
+		frecuencia[arista(b, d)]++
+		m.Apply(tourActual)
+		costoActual += delta
+	}
+
+	// 3. Bucle Principal
+	for iter := 1; iter <= config.MaxIteraciones; iter++ {
+		diversificar := iter <= finDiversificacion
+
+		// Atributos cuyo estado tabú vence en esta iteración
+		for _, k := range vencimientos[iter] {
+			if tabu[k] == iter {
+				delete(tabu, k)
+			}
+		}
+		delete(vencimientos, iter)
+
+		mejorVecinoValor := math.MaxFloat64
+		mejorVecinoDelta := 0.0
+		var mejorMov moves.TwoOpt
+		foundMove := false
+
+		// Explorar toda la vecindad 2-Opt
+		for m := range moves.VecindarioTwoOpt(n) {
+			delta := m.Delta(tourActual, dist.Dist)
+			nuevoCostoPosible := costoActual + delta
+
+			attr := m.TabuAttribute(tourActual)
+			esTabu := tabu[attr] > iter
+			if esTabu && nuevoCostoPosible < res.Costo {
+				esTabu = false // Aspiración
+			}
+			if esTabu {
+				continue
+			}
+
+			// Penalización por frecuencia durante la diversificación
+			valor := nuevoCostoPosible
+			if diversificar && nuevoCostoPosible >= res.Costo {
+				a, b := tourActual[m.I-1], tourActual[m.I]
+				c, d := tourActual[m.J], tourActual[(m.J+1)%n]
+				usos := float64(frecuencia[arista(a, c)] + frecuencia[arista(b, d)])
+				valor += config.Penalizacion * aristaMedia * usos / float64(iter)
+			}
+
+			if valor < mejorVecinoValor {
+				mejorVecinoValor = valor
+				mejorVecinoDelta = delta
+				mejorMov = m
+				foundMove = true
+			}
+		}
+
+		// 4. Moverse a la siguiente solución
+		if foundMove {
+			attr := mejorMov.TabuAttribute(tourActual)
+			mover(mejorMov, mejorVecinoDelta)
+
+			vence := iter + int(math.Round(tenencia))
+			tabu[attr] = vence
+			vencimientos[vence] = append(vencimientos[vence], attr)
+
+			if costoActual < res.Costo {
+				res.Tour = utils.CopiarPermutacion(tourActual)
+				res.Costo = costoActual
+			}
+		}
+
+		// 5. Reacción: ¿ya se visitó esta solución?
+		v, ok := visitadas[hash]
+		if ok && math.Abs(v.costo-costoActual) < 1e-6 {
+			res.Repeticiones++
+			v.veces++
+			cicloMedio = 0.1*float64(iter-v.iter) + 0.9*cicloMedio
+			tenencia = math.Min(tenencia*IncrementoTenencia+1, tenenciaMax)
+			ultimoCambio = iter
+			if v.veces == RepeticionesCaos {
+				caoticas++
+			}
+			v.iter = iter
+			visitadas[hash] = v
+
+			// Escape: movimientos aleatorios y memoria de soluciones nueva
+			if caoticas >= SolucionesCaos {
+				caoticas = 0
+				res.Escapes++
+				pasos := 1 + int((1+rand.Float64())*cicloMedio/2)
+				for k := 0; k < pasos; k++ {
+					i, j := 1+rand.Intn(n-1), 1+rand.Intn(n-1)
+					if i == j {
+						continue
+					}
+					m := moves.TwoOpt{I: min(i, j), J: max(i, j)}
+					mover(m, m.Delta(tourActual, dist.Dist))
+				}
+				clear(visitadas)
+				finDiversificacion = iter + config.FaseDiversificacion
+				if costoActual < res.Costo {
+					res.Tour = utils.CopiarPermutacion(tourActual)
+					res.Costo = costoActual
+				}
+			}
+		} else {
+			visitadas[hash] = visita{costo: costoActual, iter: iter, veces: 1}
+			if float64(iter-ultimoCambio) > cicloMedio {
+				tenencia = math.Max(tenencia*DecrementoTenencia, 1)
+				ultimoCambio = iter
+			}
+		}
+		res.TenenciaMax = max(res.TenenciaMax, int(math.Round(tenencia)))
+	}
+
+	res.TenenciaFinal = int(math.Round(tenencia))
+	return res
+}
@@ -1,110 +1,179 @@
 package main
 
 import (
+	"fmt"
+	"iter"
 	"math"
 	"math/rand"
-	"tsp-common/models"
+	"strings"
+	"tsp-common/moves"
 	"tsp-common/utils"
 )
 
-func TabuSearch(ciudades []models.City, maxIteraciones int, tenenciaTabu int) ([]models.City, float64) {
-	n := len(ciudades)
+// Vecindarios seleccionables con la opción -moves, combinables separados por coma.
+const (
+	Vecindario2Opt      = "2opt"   // Inversión de un segmento
+	VecindarioSwap      = "swap"   // Intercambio de dos ciudades
+	VecindarioInsercion = "insert" // Reinserción de una ciudad
+	VecindarioOrOpt     = "oropt"  // Traslado de un segmento de hasta LargoOrOpt ciudades
+)
+
+// Vecindarios lista los vecindarios disponibles.
+var Vecindarios = []string{Vecindario2Opt, VecindarioSwap, VecindarioInsercion, VecindarioOrOpt}
+
+// LargoOrOpt es el largo máximo de los segmentos que mueve Or-opt.
+const LargoOrOpt = 3
+
+// ParsearVecindarios separa una lista como "2opt,oropt" y devuelve error si
+// está vacía, repite un nombre o alguno no corresponde a ningún vecindario.
+func ParsearVecindarios(lista string) ([]string, error) {
+	var vecindarios []string
+	for _, v := range strings.Split(lista, ",") {
+		v = strings.TrimSpace(v)
+		valido := false
+		for _, c := range Vecindarios {
+			valido = valido || c == v
+		}
+		if !valido {
+			return nil, fmt.Errorf("vecindario desconocido %q (opciones: %v)", v, Vecindarios)
+		}
+		for _, w := range vecindarios {
+			if w == v {
+				return nil, fmt.Errorf("vecindario repetido %q", v)
+			}
+		}
+		vecindarios = append(vecindarios, v)
+	}
+	return vecindarios, nil
+}
+
+// mejorDelVecindario devuelve el movimiento de menor delta del vecindario
+// indicado entre los que satisfacen admisible, que recibe su atributo tabú
+func mejorDelVecindario(nombre string, tour []int, dist moves.Distancia, admisible func(moves.Atributo, float64) bool) (moves.Move, float64, bool) {
+	n := len(tour)
+	switch nombre {
+	case VecindarioSwap:
+		return mejorDe(tour, dist, moves.VecindarioSwap(n), admisible)
+	case VecindarioInsercion:
+		return mejorDe(tour, dist, moves.VecindarioInsercion(n), admisible)
+	case VecindarioOrOpt:
+		return mejorDe(tour, dist, moves.VecindarioOrOpt(n, LargoOrOpt), admisible)
+	default:
+		return mejorDe(tour, dist, moves.VecindarioTwoOpt(n), admisible)
+	}
+}
+
+// mejorDe recorre el vecindario con su tipo concreto, así que solo el
+// movimiento elegido se convierte en Move
+func mejorDe[M moves.Move](tour []int, dist moves.Distancia, seq iter.Seq[M], admisible func(moves.Atributo, float64) bool) (moves.Move, float64, bool) {
+	m, delta, ok := moves.MejorMovimiento(tour, dist, seq, func(m M, d float64) bool {
+		return admisible(m.TabuAttribute(tour), d)
+	})
+	return m, delta, ok
+}
+
+// claveTabu identifica un atributo en la memoria tabú de un tipo de movimiento
+type claveTabu struct {
+	tipo int
+	moves.Atributo
+}
+
+// UsoVecindario cuenta cuánto se usó un tipo de movimiento en TabuSearch
+type UsoVecindario struct {
+	Nombre    string
+	Evaluados int // Movimientos evaluados
+	Aplicados int // Veces que fue el mejor movimiento admisible
+	Mejoras   int // Veces que mejoró al mejor global
+}
+
+// TabuSearch trabaja sobre una permutación de índices de las ciudades de la
+// matriz de distancias precalculada. La vecindad es la unión de vecindarios
+// (ver Vecindarios; vacía = solo 2-opt): en cada iteración se elige el mejor
+// movimiento admisible entre todos ellos. Cada tipo de movimiento tiene su
+// propia memoria tabú, indexada por su atributo (moves.Move.TabuAttribute).
+// La memoria guarda solo los atributos vigentes, así que ocupa O(tenencia) y
+// no una matriz n×n por vecindario.
+func TabuSearch(dist utils.MatrizDistancia, n int, maxIteraciones int, tenenciaTabu int, vecindarios []string) ([]int, float64, []UsoVecindario) {
+	if len(vecindarios) == 0 {
+		vecindarios = []string{Vecindario2Opt}
+	}
+	uso := make([]UsoVecindario, len(vecindarios))
+	for t, v := range vecindarios {
+		uso[t].Nombre = v
+	}
 
 	// 1. Solución Inicial (Aleatoria o Greedy)
-	tourActual := utils.CopiarTour(ciudades)
+	tourActual := utils.PermutacionIdentidad(n)
 	rand.Shuffle(len(tourActual), func(i, j int) {
 		tourActual[i], tourActual[j] = tourActual[j], tourActual[i]
 	})
 
-	costoActual := utils.CalcularCostoTotal(tourActual)
+	costoActual := dist.CostoPermutacion(tourActual)
 
 	// Mejor solución global (Best Global)
-	tourBest := utils.CopiarTour(tourActual)
+	tourBest := utils.CopiarPermutacion(tourActual)
 	costoBest := costoActual
 
-	// 2. Estructura de Memoria Tabú
-	maxID := 0
-	for _, c := range ciudades {
-		if c.ID > maxID {
-			maxID = c.ID
-		}
-	}
-	tabuMatrix := make([][]int, maxID+1)
-	for i := range tabuMatrix {
-		tabuMatrix[i] = make([]int, maxID+1)
-	}
+	// 2. Estructura de Memoria Tabú: iteración hasta la que cada atributo es
+	// tabú, y atributos que vencen en cada iteración
+	tabu := make(map[claveTabu]int)
+	vencimientos := make(map[int][]claveTabu)
 
 	// 3. Bucle Principal
 	for iter := 1; iter <= maxIteraciones; iter++ {
 
+		// Atributos cuyo estado tabú vence en esta iteración
+		for _, k := range vencimientos[iter] {
+			if tabu[k] == iter {
+				delete(tabu, k)
+			}
+		}
+		delete(vencimientos, iter)
+
 		// Variables para encontrar el MEJOR vecino en TODA la vecindad (Best Improvement)
 		mejorVecinoCosto := math.MaxFloat64
-		moveI, moveJ := -1, -1
-
-		foundMove := false
-
-		// Explorar toda la vecindad 2-Opt
-		for i := 1; i < n-1; i++ {
-			for j := i + 1; j < n; j++ {
-
-				// A. Calcular Delta (Diferencia de costo)
-				d1 := utils.DistanciaEuclidiana(tourActual[i-1], tourActual[i])
-				d2 := utils.DistanciaEuclidiana(tourActual[j], tourActual[(j+1)%n])
-				costoAristasViejas := d1 + d2
-
-				// Costo nuevo de las aristas a crear
-				d3 := utils.DistanciaEuclidiana(tourActual[i-1], tourActual[j])
-				d4 := utils.DistanciaEuclidiana(tourActual[i], tourActual[(j+1)%n])
-				costoAristasNuevas := d3 + d4
-
-				delta := costoAristasNuevas - costoAristasViejas
-				nuevoCostoPosible := costoActual + delta
-
-				// B. Verificar Estado Tabú
-				id1 := tourActual[i].ID
-				id2 := tourActual[j].ID
-
-				esTabu := false
-				if tabuMatrix[id1][id2] > iter {
-					esTabu = true
-				}
-
-				// C. Criterio de Aspiración
-				if esTabu && (nuevoCostoPosible < costoBest) {
-					esTabu = false // Romper el tabú por aspiración
-				}
-
-				// D. Selección del mejor vecino admisible
-				if !esTabu {
-					if nuevoCostoPosible < mejorVecinoCosto {
-						mejorVecinoCosto = nuevoCostoPosible
-						moveI = i
-						moveJ = j
-						foundMove = true
-					}
-				}
+		var mejorMov moves.Move
+		mejorTipo := -1
+
+		// Explorar la unión de los vecindarios
+		for t, nombre := range vecindarios {
+			m, delta, ok := mejorDelVecindario(nombre, tourActual, dist.Dist, func(attr moves.Atributo, delta float64) bool {
+				uso[t].Evaluados++
+
+				// Verificar Estado Tabú, con Criterio de Aspiración
+				esTabu := tabu[claveTabu{t, attr}] > iter
+				return !esTabu || costoActual+delta < costoBest
+			})
+
+			// Selección del mejor vecino admisible de la unión
+			if ok && costoActual+delta < mejorVecinoCosto {
+				mejorVecinoCosto = costoActual + delta
+				mejorMov = m
+				mejorTipo = t
 			}
 		}
 
 		// 4. Moverse a la siguiente solución
-		if foundMove {
-			invertirSegmento(tourActual, moveI, moveJ)
+		if mejorTipo >= 0 {
+			attr := mejorMov.TabuAttribute(tourActual)
+			mejorMov.Apply(tourActual)
 			costoActual = mejorVecinoCosto
+			uso[mejorTipo].Aplicados++
 
 			// Actualizar la lista Tabú
-			id1 := tourActual[moveI].ID
-			id2 := tourActual[moveJ].ID
-
-			tabuMatrix[id1][id2] = iter + tenenciaTabu
-			tabuMatrix[id2][id1] = iter + tenenciaTabu
+			k := claveTabu{mejorTipo, attr}
+			vence := iter + tenenciaTabu
+			tabu[k] = vence
+			vencimientos[vence] = append(vencimientos[vence], k)
 
 			// Actualizar el mejor global si corresponde
 			if costoActual < costoBest {
-				tourBest = utils.CopiarTour(tourActual)
+				tourBest = utils.CopiarPermutacion(tourActual)
 				costoBest = costoActual
+				uso[mejorTipo].Mejoras++
 			}
 		}
 	}
 
-	return tourBest, costoBest
+	return tourBest, costoBest, uso
 }
@@ -0,0 +1,301 @@
+// Package kdtree implementa un árbol k-d (k = 2) sobre las coordenadas de las
+// ciudades, con consultas de vecino más cercano, k vecinos más cercanos, radio
+// y más lejano. Los puntos se pueden eliminar (y restaurar todos de una vez),
+// de modo que las heurísticas constructivas preguntan por "la ciudad no
+// visitada más cercana" en O(log n) esperado en lugar de recorrer las n.
+package kdtree
+
+import (
+	"sort"
+	"tsp-common/models"
+)
+
+// Arbol es un árbol k-d implícito: el subárbol del rango [lo, hi) de orden
+// tiene su raíz en la posición (lo+hi)/2, el hijo izquierdo en [lo, m) y el
+// derecho en [m+1, hi). Las ciudades se identifican por su índice en el
+// slice recibido por Nuevo.
+type Arbol struct {
+	ciudades  []models.City
+	orden     []int  // orden[p] = ciudad en la posición p del árbol
+	pos       []int  // pos[c] = posición de la ciudad c en orden
+	eje       []bool // eje[p] = true si el nodo p corta por Y, false si por X
+	caja      []caja // caja[p] = rectángulo que contiene al subárbol con raíz p
+	vivos     []int  // vivos[p] = ciudades no eliminadas en el subárbol con raíz p
+	tam       []int  // tam[p] = tamaño del subárbol con raíz p (para Restaurar)
+	eliminado []bool
+}
+
+type caja struct {
+	minX, minY, maxX, maxY float64
+}
+
+// dist2 es la distancia al cuadrado del punto al rectángulo (0 si está dentro).
+func (b caja) dist2(x, y float64) float64 {
+	dx := max(b.minX-x, 0, x-b.maxX)
+	dy := max(b.minY-y, 0, y-b.maxY)
+	return dx*dx + dy*dy
+}
+
+// dist2Max es la distancia al cuadrado del punto a la esquina más lejana.
+func (b caja) dist2Max(x, y float64) float64 {
+	dx := max(x-b.minX, b.maxX-x)
+	dy := max(y-b.minY, b.maxY-y)
+	return dx*dx + dy*dy
+}
+
+// Nuevo construye el árbol en O(n log^2 n). Todas las ciudades empiezan vivas.
+func Nuevo(ciudades []models.City) *Arbol {
+	n := len(ciudades)
+	a := &Arbol{
+		ciudades:  ciudades,
+		orden:     make([]int, n),
+		pos:       make([]int, n),
+		eje:       make([]bool, n),
+		caja:      make([]caja, n),
+		vivos:     make([]int, n),
+		tam:       make([]int, n),
+		eliminado: make([]bool, n),
+	}
+	for i := range a.orden {
+		a.orden[i] = i
+	}
+	a.construir(0, n)
+	for p, c := range a.orden {
+		a.pos[c] = p
+	}
+	copy(a.vivos, a.tam)
+	return a
+}
+
+// NuevoDesdeCoordenadas construye el árbol sobre puntos (x, y); la ciudad i
+// es coords[i].
+func NuevoDesdeCoordenadas(coords [][2]float64) *Arbol {
+	ciudades := make([]models.City, len(coords))
+	for i, c := range coords {
+		ciudades[i] = models.City{ID: i + 1, X: c[0], Y: c[1]}
+	}
+	return Nuevo(ciudades)
+}
+
+// construir ordena orden[lo:hi] alrededor de la mediana del eje más extendido.
+func (a *Arbol) construir(lo, hi int) {
+	if lo >= hi {
+		return
+	}
+	b := caja{minX: a.ciudades[a.orden[lo]].X, minY: a.ciudades[a.orden[lo]].Y}
+	b.maxX, b.maxY = b.minX, b.minY
+	for _, c := range a.orden[lo:hi] {
+		p := a.ciudades[c]
+		b.minX, b.maxX = min(b.minX, p.X), max(b.maxX, p.X)
+		b.minY, b.maxY = min(b.minY, p.Y), max(b.maxY, p.Y)
+	}
+
+	m := (lo + hi) / 2
+	porY := b.maxY-b.minY > b.maxX-b.minX
+	tramo := a.orden[lo:hi]
+	sort.Slice(tramo, func(i, j int) bool {
+		return a.coord(tramo[i], porY) < a.coord(tramo[j], porY)
+	})
+	a.eje[m], a.caja[m], a.tam[m] = porY, b, hi-lo
+
+	a.construir(lo, m)
+	a.construir(m+1, hi)
+}
+
+func (a *Arbol) coord(c int, porY bool) float64 {
+	if porY {
+		return a.ciudades[c].Y
+	}
+	return a.ciudades[c].X
+}
+
+func (a *Arbol) dist2(c int, x, y float64) float64 {
+	dx, dy := a.ciudades[c].X-x, a.ciudades[c].Y-y
+	return dx*dx + dy*dy
+}
+
+// Vivos devuelve la cantidad de ciudades no eliminadas.
+func (a *Arbol) Vivos() int {
+	if len(a.orden) == 0 {
+		return 0
+	}
+	return a.vivos[len(a.orden)/2]
+}
+
+// Eliminado indica si la ciudad i fue eliminada.
+func (a *Arbol) Eliminado(i int) bool { return a.eliminado[i] }
+
+// Eliminar saca la ciudad i de las consultas en O(log n).
+func (a *Arbol) Eliminar(i int) {
+	if a.eliminado[i] {
+		return
+	}
+	a.eliminado[i] = true
+	p := a.pos[i]
+	for lo, hi := 0, len(a.orden); lo < hi; {
+		m := (lo + hi) / 2
+		a.vivos[m]--
+		if p == m {
+			return
+		}
+		if p < m {
+			hi = m
+		} else {
+			lo = m + 1
+		}
+	}
+}
+
+// Restaurar vuelve a dejar vivas todas las ciudades en O(n).
+func (a *Arbol) Restaurar() {
+	clear(a.eliminado)
+	copy(a.vivos, a.tam)
+}
+
+// Cercano devuelve la ciudad viva más cercana al punto p. ok es false si no
+// quedan ciudades vivas.
+func (a *Arbol) Cercano(p models.City) (int, bool) {
+	mejor, mejorD2 := -1, 0.0
+	var buscar func(lo, hi int)
+	buscar = func(lo, hi int) {
+		if lo >= hi {
+			return
+		}
+		m := (lo + hi) / 2
+		if a.vivos[m] == 0 || (mejor >= 0 && a.caja[m].dist2(p.X, p.Y) >= mejorD2) {
+			return
+		}
+		c := a.orden[m]
+		if !a.eliminado[c] {
+			if d2 := a.dist2(c, p.X, p.Y); mejor < 0 || d2 < mejorD2 {
+				mejor, mejorD2 = c, d2
+			}
+		}
+		// Primero el lado del corte donde cae el punto
+		if a.coord(c, a.eje[m]) > coordPunto(p, a.eje[m]) {
+			buscar(lo, m)
+			buscar(m+1, hi)
+		} else {
+			buscar(m+1, hi)
+			buscar(lo, m)
+		}
+	}
+	buscar(0, len(a.orden))
+	return mejor, mejor >= 0
+}
+
+// Lejano devuelve la ciudad viva más lejana al punto p. ok es false si no
+// quedan ciudades vivas.
+func (a *Arbol) Lejano(p models.City) (int, bool) {
+	mejor, mejorD2 := -1, 0.0
+	var buscar func(lo, hi int)
+	buscar = func(lo, hi int) {
+		if lo >= hi {
+			return
+		}
+		m := (lo + hi) / 2
+		if a.vivos[m] == 0 || (mejor >= 0 && a.caja[m].dist2Max(p.X, p.Y) <= mejorD2) {
+			return
+		}
+		c := a.orden[m]
+		if !a.eliminado[c] {
+			if d2 := a.dist2(c, p.X, p.Y); mejor < 0 || d2 > mejorD2 {
+				mejor, mejorD2 = c, d2
+			}
+		}
+		// Primero el lado opuesto al punto
+		if a.coord(c, a.eje[m]) > coordPunto(p, a.eje[m]) {
+			buscar(m+1, hi)
+			buscar(lo, m)
+		} else {
+			buscar(lo, m)
+			buscar(m+1, hi)
+		}
+	}
+	buscar(0, len(a.orden))
+	return mejor, mejor >= 0
+}
+
+// KCercanos devuelve las k ciudades vivas más cercanas al punto p, de menor a
+// mayor distancia (menos de k si no quedan suficientes).
+func (a *Arbol) KCercanos(p models.City, k int) []int {
+	if k <= 0 {
+		return nil
+	}
+	// mejores se mantiene ordenada por distancia; k es chico, así que la
+	// inserción lineal es más barata que un heap
+	mejores := make([]candidato, 0, k)
+	var buscar func(lo, hi int)
+	buscar = func(lo, hi int) {
+		if lo >= hi {
+			return
+		}
+		m := (lo + hi) / 2
+		if a.vivos[m] == 0 || (len(mejores) == k && a.caja[m].dist2(p.X, p.Y) >= mejores[k-1].d2) {
+			return
+		}
+		c := a.orden[m]
+		if !a.eliminado[c] {
+			if d2 := a.dist2(c, p.X, p.Y); len(mejores) < k || d2 < mejores[k-1].d2 {
+				if len(mejores) < k {
+					mejores = append(mejores, candidato{})
+				}
+				i := len(mejores) - 1
+				for ; i > 0 && mejores[i-1].d2 > d2; i-- {
+					mejores[i] = mejores[i-1]
+				}
+				mejores[i] = candidato{c, d2}
+			}
+		}
+		if a.coord(c, a.eje[m]) > coordPunto(p, a.eje[m]) {
+			buscar(lo, m)
+			buscar(m+1, hi)
+		} else {
+			buscar(m+1, hi)
+			buscar(lo, m)
+		}
+	}
+	buscar(0, len(a.orden))
+
+	res := make([]int, len(mejores))
+	for i, m := range mejores {
+		res[i] = m.ciudad
+	}
+	return res
+}
+
+// EnRadio devuelve las ciudades vivas a distancia <= r del punto p, sin
+// un orden particular.
+func (a *Arbol) EnRadio(p models.City, r float64) []int {
+	r2 := r * r
+	var res []int
+	var buscar func(lo, hi int)
+	buscar = func(lo, hi int) {
+		if lo >= hi {
+			return
+		}
+		m := (lo + hi) / 2
+		if a.vivos[m] == 0 || a.caja[m].dist2(p.X, p.Y) > r2 {
+			return
+		}
+		if c := a.orden[m]; !a.eliminado[c] && a.dist2(c, p.X, p.Y) <= r2 {
+			res = append(res, c)
+		}
+		buscar(lo, m)
+		buscar(m+1, hi)
+	}
+	buscar(0, len(a.orden))
+	return res
+}
+
+func coordPunto(p models.City, porY bool) float64 {
+	if porY {
+		return p.Y
+	}
+	return p.X
+}
+
+type candidato struct {
+	ciudad int
+	d2     float64
+}
@@ -0,0 +1,226 @@
+// Package moves reúne los movimientos de vecindad sobre tours representados
+// como permutaciones de índices. Cada movimiento calcula su delta de costo sin
+// modificar el tour, y se aplica y deshace en su lugar, de modo que Tabu, SA,
+// la quimiotaxis de OFP y la búsqueda local comparten el mismo código.
+package moves
+
+// Distancia devuelve el costo de la arista entre dos ciudades (por índice),
+// por ejemplo utils.MatrizDistancia.Dist.
+type Distancia func(a, b int) float64
+
+// Atributo identifica un movimiento en la memoria tabú: el par (sin orden) de
+// ciudades que lo caracteriza.
+type Atributo struct {
+	A, B int
+}
+
+func nuevoAtributo(a, b int) Atributo {
+	if a > b {
+		a, b = b, a
+	}
+	return Atributo{A: a, B: b}
+}
+
+// Move es un movimiento de vecindad expresado con posiciones del tour.
+type Move interface {
+	// Delta devuelve costo(vecino) - costo(tour) sin modificar el tour.
+	Delta(tour []int, dist Distancia) float64
+	// Apply transforma el tour en el vecino, en su lugar.
+	Apply(tour []int)
+	// Undo revierte Apply sobre el tour resultante.
+	Undo(tour []int)
+	// TabuAttribute devuelve el atributo del movimiento evaluado sobre el
+	// tour antes de aplicarlo.
+	TabuAttribute(tour []int) Atributo
+}
+
+// TwoOpt invierte el segmento tour[I..J]: quita las aristas (I-1, I) y
+// (J, J+1) y agrega (I-1, J) e (I, J+1). Requiere 0 <= I < J <= n-1 y que
+// no sea I == 0 con J == n-1 (invertir el tour completo no lo cambia).
+type TwoOpt struct {
+	I, J int
+}
+
+func (m TwoOpt) Delta(tour []int, dist Distancia) float64 {
+	n := len(tour)
+	a, b := tour[(m.I-1+n)%n], tour[m.I]
+	c, d := tour[m.J], tour[(m.J+1)%n]
+	return (dist(a, c) + dist(b, d)) - (dist(a, b) + dist(c, d))
+}
+
+func (m TwoOpt) Apply(tour []int) { invertir(tour, m.I, m.J) }
+
+func (m TwoOpt) Undo(tour []int) { invertir(tour, m.I, m.J) }
+
+// TabuAttribute es el par de ciudades de los extremos del segmento; como la
+// inversión solo los intercambia, coincide antes y después de aplicarlo.
+func (m TwoOpt) TabuAttribute(tour []int) Atributo {
+	return nuevoAtributo(tour[m.I], tour[m.J])
+}
+
+// Swap intercambia las ciudades de las posiciones I y J (I < J).
+type Swap struct {
+	I, J int
+}
+
+func (m Swap) Delta(tour []int, dist Distancia) float64 {
+	n := len(tour)
+	// en devuelve la ciudad en la posición p después del intercambio
+	en := func(p int) int {
+		switch p {
+		case m.I:
+			return tour[m.J]
+		case m.J:
+			return tour[m.I]
+		}
+		return tour[p]
+	}
+
+	antes, despues := 0.0, 0.0
+	for _, p := range aristasAfectadas(n, m.I-1, m.I, m.J-1, m.J) {
+		q := (p + 1) % n
+		antes += dist(tour[p], tour[q])
+		despues += dist(en(p), en(q))
+	}
+	return despues - antes
+}
+
+func (m Swap) Apply(tour []int) { tour[m.I], tour[m.J] = tour[m.J], tour[m.I] }
+
+func (m Swap) Undo(tour []int) { m.Apply(tour) }
+
+func (m Swap) TabuAttribute(tour []int) Atributo {
+	return nuevoAtributo(tour[m.I], tour[m.J])
+}
+
+// Insercion saca la ciudad de la posición De y la reinserta para que quede en
+// la posición A. Se excluyen De == A y los pares {0, n-1}, que dejan el mismo ciclo.
+type Insercion struct {
+	De, A int
+}
+
+func (m Insercion) Delta(tour []int, dist Distancia) float64 {
+	n := len(tour)
+	x := tour[m.De]
+	p, q := tour[(m.De-1+n)%n], tour[(m.De+1)%n]
+
+	// (u, v) es la arista del tour original donde se inserta x
+	var u, v int
+	if m.A > m.De {
+		u, v = tour[m.A], tour[(m.A+1)%n]
+	} else {
+		u, v = tour[(m.A-1+n)%n], tour[m.A]
+	}
+	return (dist(p, q) + dist(u, x) + dist(x, v)) - (dist(p, x) + dist(x, q) + dist(u, v))
+}
+
+func (m Insercion) Apply(tour []int) {
+	x := tour[m.De]
+	if m.De < m.A {
+		copy(tour[m.De:m.A], tour[m.De+1:m.A+1])
+	} else {
+		copy(tour[m.A+1:m.De+1], tour[m.A:m.De])
+	}
+	tour[m.A] = x
+}
+
+func (m Insercion) Undo(tour []int) { Insercion{De: m.A, A: m.De}.Apply(tour) }
+
+// TabuAttribute es el par (ciudad movida, su predecesor original).
+func (m Insercion) TabuAttribute(tour []int) Atributo {
+	n := len(tour)
+	return nuevoAtributo(tour[m.De], tour[(m.De-1+n)%n])
+}
+
+// OrOpt mueve el segmento de L ciudades que empieza en la posición I (sin dar
+// la vuelta al arreglo) para que quede a continuación de la posición J, sin
+// invertirlo. J no puede estar en I-1..I+L-1.
+type OrOpt struct {
+	I, L, J int
+}
+
+func (m OrOpt) Delta(tour []int, dist Distancia) float64 {
+	n := len(tour)
+	p, q := tour[(m.I-1+n)%n], tour[(m.I+m.L)%n]
+	s1, sL := tour[m.I], tour[m.I+m.L-1]
+	u, v := tour[m.J], tour[(m.J+1)%n]
+	return (dist(p, q) + dist(u, s1) + dist(sL, v)) - (dist(p, s1) + dist(sL, q) + dist(u, v))
+}
+
+func (m OrOpt) Apply(tour []int) {
+	if m.J > m.I {
+		// tour[I..J] = S M  ->  M S
+		rotarIzquierda(tour[m.I:m.J+1], m.L)
+	} else {
+		// tour[J+1..I+L-1] = M S  ->  S M
+		rotarIzquierda(tour[m.J+1:m.I+m.L], m.I-m.J-1)
+	}
+}
+
+func (m OrOpt) Undo(tour []int) {
+	if m.J > m.I {
+		OrOpt{I: m.J - m.L + 1, L: m.L, J: m.I - 1}.Apply(tour)
+	} else {
+		OrOpt{I: m.J + 1, L: m.L, J: m.I + m.L - 1}.Apply(tour)
+	}
+}
+
+func (m OrOpt) TabuAttribute(tour []int) Atributo {
+	return nuevoAtributo(tour[m.I], tour[m.I+m.L-1])
+}
+
+// DoubleBridge corta el tour en A = [0, P1), B = [P1, P2), C = [P2, P3),
+// D = [P3, n) y lo reordena como A C B D. Requiere 0 < P1 < P2 < P3 < n.
+type DoubleBridge struct {
+	P1, P2, P3 int
+}
+
+func (m DoubleBridge) Delta(tour []int, dist Distancia) float64 {
+	a, b1, b2 := tour[m.P1-1], tour[m.P1], tour[m.P2-1]
+	c1, c2, d := tour[m.P2], tour[m.P3-1], tour[m.P3]
+	return (dist(a, c1) + dist(c2, b1) + dist(b2, d)) - (dist(a, b1) + dist(b2, c1) + dist(c2, d))
+}
+
+func (m DoubleBridge) Apply(tour []int) { rotarIzquierda(tour[m.P1:m.P3], m.P2-m.P1) }
+
+func (m DoubleBridge) Undo(tour []int) { rotarIzquierda(tour[m.P1:m.P3], m.P3-m.P2) }
+
+func (m DoubleBridge) TabuAttribute(tour []int) Atributo {
+	return nuevoAtributo(tour[m.P1], tour[m.P3-1])
+}
+
+// invertir invierte tour[i..j] en su lugar.
+func invertir(tour []int, i, j int) {
+	for i < j {
+		tour[i], tour[j] = tour[j], tour[i]
+		i++
+		j--
+	}
+}
+
+// rotarIzquierda desplaza s k lugares a la izquierda con tres inversiones.
+func rotarIzquierda(s []int, k int) {
+	invertir(s, 0, k-1)
+	invertir(s, k, len(s)-1)
+	invertir(s, 0, len(s)-1)
+}
+
+// aristasAfectadas normaliza las posiciones de inicio de arista módulo n y
+// descarta repetidas.
+func aristasAfectadas(n int, posiciones ...int) []int {
+	out := make([]int, 0, len(posiciones))
+	for _, p := range posiciones {
+		p = (p + n) % n
+		repetida := false
+		for _, q := range out {
+			if q == p {
+				repetida = true
+				break
+			}
+		}
+		if !repetida {
+			out = append(out, p)
+		}
+	}
+	return out
+}
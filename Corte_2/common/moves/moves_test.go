@@ -0,0 +1,95 @@
+package moves
+
+import (
+	"iter"
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// instanciaAleatoria devuelve un tour aleatorio de n ciudades con su matriz
+// de distancias euclidianas
+func instanciaAleatoria(r *rand.Rand, n int) ([]int, Distancia) {
+	x, y := make([]float64, n), make([]float64, n)
+	for i := range x {
+		x[i], y[i] = r.Float64()*1000, r.Float64()*1000
+	}
+	d := make([][]float64, n)
+	for i := range d {
+		d[i] = make([]float64, n)
+		for j := range d[i] {
+			d[i][j] = math.Hypot(x[i]-x[j], y[i]-y[j])
+		}
+	}
+	return r.Perm(n), func(a, b int) float64 { return d[a][b] }
+}
+
+func costo(tour []int, dist Distancia) float64 {
+	total := 0.0
+	for i := range tour {
+		total += dist(tour[i], tour[(i+1)%len(tour)])
+	}
+	return total
+}
+
+func esPermutacion(tour []int) bool {
+	visto := make([]bool, len(tour))
+	for _, c := range tour {
+		if c < 0 || c >= len(tour) || visto[c] {
+			return false
+		}
+		visto[c] = true
+	}
+	return true
+}
+
+// verificar comprueba cada movimiento de seq sobre tour: el delta coincide con
+// la diferencia de costo recalculada, el resultado sigue siendo una
+// permutación y Undo deja el tour como estaba
+func verificar[M Move](t *testing.T, tour []int, dist Distancia, seq iter.Seq[M]) {
+	t.Helper()
+	original := slices.Clone(tour)
+	base := costo(tour, dist)
+	for m := range seq {
+		delta := m.Delta(tour, dist)
+		m.Apply(tour)
+		if !esPermutacion(tour) {
+			t.Fatalf("%T%+v: el resultado no es una permutación: %v", m, m, tour)
+		}
+		if real := costo(tour, dist) - base; math.Abs(real-delta) > 1e-6 {
+			t.Fatalf("%T%+v: Delta = %.6f, diferencia recalculada = %.6f", m, m, delta, real)
+		}
+		m.Undo(tour)
+		if !slices.Equal(tour, original) {
+			t.Fatalf("%T%+v: Undo no restaura el tour: %v, esperado %v", m, m, tour, original)
+		}
+	}
+}
+
+// doblesPuentes genera cantidad movimientos double bridge al azar
+func doblesPuentes(r *rand.Rand, n, cantidad int) iter.Seq[DoubleBridge] {
+	return func(yield func(DoubleBridge) bool) {
+		for k := 0; k < cantidad; k++ {
+			p := r.Perm(n - 1)[:3]
+			slices.Sort(p)
+			if !yield(DoubleBridge{P1: p[0] + 1, P2: p[1] + 1, P3: p[2] + 1}) {
+				return
+			}
+		}
+	}
+}
+
+func TestMovimientos(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, n := range []int{5, 6, 8, 13, 30} {
+		for rep := 0; rep < 5; rep++ {
+			tour, dist := instanciaAleatoria(r, n)
+			verificar(t, tour, dist, VecindarioTwoOpt(n))
+			verificar(t, tour, dist, VecindarioSwap(n))
+			verificar(t, tour, dist, VecindarioInsercion(n))
+			verificar(t, tour, dist, VecindarioOrOpt(n, 3))
+			verificar(t, tour, dist, doblesPuentes(r, n, 50))
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package moves
+
+import "iter"
+
+// VecindarioTwoOpt recorre los movimientos 2-opt con 1 <= I < J <= n-1, en
+// el mismo orden que los bucles anidados de Tabu y la búsqueda local.
+func VecindarioTwoOpt(n int) iter.Seq[TwoOpt] {
+	return func(yield func(TwoOpt) bool) {
+		for i := 1; i < n-1; i++ {
+			for j := i + 1; j < n; j++ {
+				if !yield(TwoOpt{I: i, J: j}) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// VecindarioSwap recorre todos los intercambios de dos posiciones.
+func VecindarioSwap(n int) iter.Seq[Swap] {
+	return func(yield func(Swap) bool) {
+		for i := 0; i < n-1; i++ {
+			for j := i + 1; j < n; j++ {
+				if !yield(Swap{I: i, J: j}) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// VecindarioInsercion recorre todas las reinserciones de una ciudad,
+// omitiendo las que dejan el mismo ciclo.
+func VecindarioInsercion(n int) iter.Seq[Insercion] {
+	return func(yield func(Insercion) bool) {
+		for de := 0; de < n; de++ {
+			for a := 0; a < n; a++ {
+				if a == de || (de == 0 && a == n-1) || (de == n-1 && a == 0) {
+					continue
+				}
+				if !yield(Insercion{De: de, A: a}) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// VecindarioOrOpt recorre los movimientos Or-opt con segmentos de 1 a
+// maxLargo ciudades (típicamente 3).
+func VecindarioOrOpt(n, maxLargo int) iter.Seq[OrOpt] {
+	return func(yield func(OrOpt) bool) {
+		for l := 1; l <= maxLargo && l < n-1; l++ {
+			for i := 0; i+l <= n; i++ {
+				for j := 0; j < n; j++ {
+					if j >= i-1 && j <= i+l-1 {
+						continue
+					}
+					// Con el segmento al inicio, insertarlo tras la última
+					// posición deja el mismo ciclo
+					if i == 0 && j == n-1 {
+						continue
+					}
+					if !yield(OrOpt{I: i, L: l, J: j}) {
+						return
+					}
+				}
+			}
+		}
+	}
+}
+
+// Movimientos convierte un vecindario concreto en uno de Move, para combinar
+// vecindarios distintos en el mismo recorrido.
+func Movimientos[M Move](seq iter.Seq[M]) iter.Seq[Move] {
+	return func(yield func(Move) bool) {
+		for m := range seq {
+			if !yield(m) {
+				return
+			}
+		}
+	}
+}
+
+// MejorMovimiento devuelve el movimiento de menor delta del vecindario que
+// satisface admisible (nil acepta todos). ok es false si no hubo candidatos.
+func MejorMovimiento[M Move](tour []int, dist Distancia, seq iter.Seq[M], admisible func(M, float64) bool) (mejor M, delta float64, ok bool) {
+	for m := range seq {
+		d := m.Delta(tour, dist)
+		if admisible != nil && !admisible(m, d) {
+			continue
+		}
+		if !ok || d < delta {
+			mejor, delta, ok = m, d, true
+		}
+	}
+	return mejor, delta, ok
+}
@@ -0,0 +1,82 @@
+package utils
+
+import "tsp-common/models"
+
+// MaxCiudadesMatriz es la instancia más grande para la que
+// NuevaMatrizDistancia precalcula la matriz completa (~72 MB con 3000
+// ciudades); por encima las distancias se calculan desde las coordenadas en
+// cada consulta, con memoria O(n).
+const MaxCiudadesMatriz = 3000
+
+// MatrizDistancia guarda las distancias entre todas las ciudades, indexadas
+// por la posición de cada ciudad en el slice leído del archivo. Se calcula
+// una sola vez con DistanciaEuclidiana, así que los valores son idénticos a
+// los que se obtendrían llamándola en cada evaluación.
+type MatrizDistancia struct {
+	n        int
+	d        []float64 // nil por encima de MaxCiudadesMatriz; ver Dist
+	ciudades []models.City
+}
+
+// NuevaMatrizDistancia precalcula la matriz completa (O(n^2) en tiempo y
+// memoria) si la instancia tiene hasta MaxCiudadesMatriz ciudades.
+func NuevaMatrizDistancia(ciudades []models.City) MatrizDistancia {
+	n := len(ciudades)
+	m := MatrizDistancia{n: n, ciudades: ciudades}
+	if n > MaxCiudadesMatriz {
+		return m
+	}
+	m.d = make([]float64, n*n)
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			dist := DistanciaEuclidiana(ciudades[i], ciudades[j])
+			m.d[i*n+j] = dist
+			m.d[j*n+i] = dist
+		}
+	}
+	return m
+}
+
+// Dist devuelve la distancia entre las ciudades de índices i y j, de la
+// matriz si se precalculó o si no de las coordenadas.
+func (m MatrizDistancia) Dist(i, j int) float64 {
+	if m.d != nil {
+		return m.d[i*m.n+j]
+	}
+	return DistanciaEuclidiana(m.ciudades[i], m.ciudades[j])
+}
+
+// CostoPermutacion suma las aristas del tour en el mismo orden que CalcularCostoTotal.
+func (m MatrizDistancia) CostoPermutacion(tour []int) float64 {
+	total := 0.0
+	for i := 0; i < len(tour)-1; i++ {
+		total += m.Dist(tour[i], tour[i+1])
+	}
+	total += m.Dist(tour[len(tour)-1], tour[0])
+	return total
+}
+
+// PermutacionIdentidad devuelve [0, 1, ..., n-1].
+func PermutacionIdentidad(n int) []int {
+	tour := make([]int, n)
+	for i := range tour {
+		tour[i] = i
+	}
+	return tour
+}
+
+// CopiarPermutacion devuelve una copia independiente del tour.
+func CopiarPermutacion(tour []int) []int {
+	nueva := make([]int, len(tour))
+	copy(nueva, tour)
+	return nueva
+}
+
+// PermutacionACiudades decodifica un tour de índices al slice de ciudades.
+func PermutacionACiudades(tour []int, ciudades []models.City) []models.City {
+	out := make([]models.City, len(tour))
+	for i, idx := range tour {
+		out[i] = ciudades[idx]
+	}
+	return out
+}
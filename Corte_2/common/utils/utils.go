@@ -23,3 +23,13 @@ func CopiarTour(tour []models.City) []models.City {
 	copy(nueva, tour)
 	return nueva
 }
+
+// Coordenadas devuelve los puntos (x, y) de las ciudades, en el formato que
+// piden las heurísticas de Corte_1/Heuristica.
+func Coordenadas(ciudades []models.City) [][2]float64 {
+	coords := make([][2]float64, len(ciudades))
+	for i, c := range ciudades {
+		coords[i] = [2]float64{c.X, c.Y}
+	}
+	return coords
+}
@@ -1,11 +1,11 @@
 package geneticalgorithm
 
 import (
+	"heuristica/kopt"
 	"math/rand"
 	"sort"
 	"tsp-meme/models"
 	"tsp-meme/utils"
-	"tsp-meme/localsearch"
 )
 
 // GAConfig holds the genetic algorithm parameters.
@@ -15,7 +15,9 @@ type GAConfig struct {
 	MutationRate   float64 // Mutation probability
 	TournamentSize int     // Tournament size for selection
 	StagnationLimit int    // Stop after this many generations without improvement (0 = disabled)
+	Seed            string  // Seed individual heuristic, one of Seeds (see SeedTour)
 	NumParents      int     // NUEVO: Número de padres para la recombinación (ej. 3)
+	LocalSearch     string  // Búsqueda local aplicada a cada hijo: "2opt", "3opt", "lk" o "clk"
 }
 
 // Individual represents a candidate solution (genotype: index permutation).
@@ -77,27 +79,27 @@ func isDuplicate(pop []Individual, cost float64) bool {
 }
 
 // initPopulation creates the initial population with:
-//   - 1 Farthest Insertion individual
-//   - ~15% perturbed variants of the FI tour
+//   - 1 seed individual built by SeedTour (Farthest Insertion by default)
+//   - ~15% perturbed variants of the seed tour
 //   - ~85% random permutations
 //   - Duplicate costs are discarded and regenerated.
-func initPopulation(cities []models.City, popSize int) []Individual {
+func initPopulation(cities []models.City, popSize int, seed string) []Individual {
 	n := len(cities)
 	pop := make([]Individual, 0, popSize)
 
-	// 1. Farthest Insertion seed
-	fiTour := FarthestInsertion(cities)
-	fiCost := EvaluateCost(fiTour, cities)
-	pop = append(pop, Individual{Tour: fiTour, Cost: fiCost})
+	// 1. Constructive seed
+	seedTour := SeedTour(cities, seed)
+	seedCost := EvaluateCost(seedTour, cities)
+	pop = append(pop, Individual{Tour: seedTour, Cost: seedCost})
 
-	// 2. Perturbed variants of FI tour (~15% of population)
+	// 2. Perturbed variants of the seed tour (~15% of population)
 	numPerturbed := popSize * 15 / 100
 	swaps := n / 5 // number of swaps per perturbation
 	if swaps < 2 {
 		swaps = 2
 	}
 	for i := 0; i < numPerturbed; i++ {
-		pt := perturbTour(fiTour, swaps)
+		pt := perturbTour(seedTour, swaps)
 		cost := EvaluateCost(pt, cities)
 		if !isDuplicate(pop, cost) {
 			pop = append(pop, Individual{Tour: pt, Cost: cost})
@@ -139,7 +141,12 @@ func RunGA(cities []models.City, config GAConfig) GAResult {
 	n := len(cities)
 
 	// 1. Initialize diverse population
-	population := initPopulation(cities, config.PopSize)
+	population := initPopulation(cities, config.PopSize, config.Seed)
+
+	// Local search prepared once: its neighbor lists are shared by every child
+	ls := kopt.NuevaBusquedaLocal(config.LocalSearch, coordinates(cities), func(i, j int) float64 {
+		return utils.DistanciaEuclidiana(cities[i], cities[j])
+	})
 
 	// Find initial best
 	best := population[0]
@@ -177,7 +184,7 @@ func RunGA(cities []models.City, config GAConfig) GAResult {
 			}
 
 			// 4. BÚSQUEDA LOCAL (EL NÚCLEO DEL ALGORITMO MEMÉTICO - Inciso B)
-			childTourOpt, childCost := localsearch.TwoOpt(childTour, cities)
+			childTourOpt, childCost := ls.Optimizar(childTour)
 			
 			// 5. Evaluar hijo YA OPTIMIZADO y añadirlo
 			offspring = append(offspring, Individual{Tour: childTourOpt, Cost: childCost})
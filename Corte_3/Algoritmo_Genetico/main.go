@@ -3,6 +3,8 @@ package main
 import (
 	"flag"
 	"fmt"
+	"heuristica/kopt"
+	"heuristica/tsp"
 	"math/rand"
 	"path/filepath"
 	"time"
@@ -21,11 +23,29 @@ func main() {
 	tourn := flag.Int("tourn", 3, "Tamaño del torneo para seleccion")
 	stag := flag.Int("stag", 200, "Generaciones sin mejora antes de parar (0 = desactivado)")
 	parents := flag.Int("parents", 3, "Numero de padres para recombinacion (>= 3)") 
+	ls := flag.String("ls", kopt.Metodo2Opt, "Busqueda local aplicada a cada hijo (2opt, 3opt, lk, clk)")
+	semilla := flag.String("init", geneticalgorithm.SeedFarthest, "Heuristica constructiva del individuo semilla (farthest, greedy, hilbert, sierpinski, christofides, double-tree)")
+	ventana := flag.Int("ventana", 0, "Tamaño de la ventana de reoptimizacion exacta final (0 = desactivado, 4..16)")
 	flat := flag.Bool("flat", false, "Mostrar informacion en formato plano (sin encabezados)")
 
 	// Parsear los argumentos de la linea de comandos
 	flag.Parse()
 
+	if err := kopt.ValidarMetodo(*ls); err != nil {
+		fmt.Printf("ERROR: %v\n", err)
+		return
+	}
+
+	if err := geneticalgorithm.ValidateSeed(*semilla); err != nil {
+		fmt.Printf("ERROR: %v\n", err)
+		return
+	}
+
+	if err := tsp.ValidateWindow(*ventana); err != nil {
+		fmt.Printf("ERROR: %v\n", err)
+		return
+	}
+
 	// Ruta por defecto o por argumento
 	archivo := "../Benchmark/berlin52.tsp"
 	args := flag.Args()
@@ -48,7 +68,9 @@ func main() {
 		MutationRate:    *mut,
 		TournamentSize:  *tourn,
 		StagnationLimit: *stag,
+		Seed:            *semilla,
 		NumParents:      *parents,
+		LocalSearch:     *ls,
 	}
 
 	start := time.Now()
@@ -56,6 +78,12 @@ func main() {
 	// 2. Ejecutar Algoritmo Memético (antes Genético)
 	result := solver.GeneticAlgorithmSolver(ciudades, configGA)
 
+	// Post-proceso opcional: reoptimización exacta por ventanas
+	if *ventana > 0 {
+		result.BestTour = tsp.WindowReoptimizeSlice(result.BestTour, utils.DistanciaEuclidiana, *ventana)
+		result.BestCost = utils.CalcularCostoTotal(result.BestTour)
+	}
+
 	elapsed := time.Since(start)
 
 	// 3. Calculo del GAP
@@ -78,8 +106,8 @@ func main() {
 			"Benchmark", "Tiempo", "Costo", "Optimo", "GAP AM (%)") 
 		fmt.Printf("%s\t%s\t%.4f\t%.0f\t%.2f\n",
 			nombreArchivo, elapsed, result.BestCost, optimo, gapGA)
-		fmt.Printf("Configuracion AM: Pop=%d, Gen=%d, Mut=%.4f, Tourn=%d, Stag=%d, Parents=%d\n",
-			*pop, *gen, *mut, *tourn, *stag, *parents)
+		fmt.Printf("Configuracion AM: Pop=%d, Gen=%d, Mut=%.4f, Tourn=%d, Stag=%d, Parents=%d, LS=%s, Init=%s, Ventana=%d\n",
+			*pop, *gen, *mut, *tourn, *stag, *parents, *ls, *semilla, *ventana)
 		fmt.Printf("Convergencia: ultima mejora en gen %d, parada en gen %d por %s\n",
 			result.LastImproveGen, result.TotalGens, result.StopReason)
 	}
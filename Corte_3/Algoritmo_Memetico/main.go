@@ -3,6 +3,8 @@ package main
 import (
 	"fmt"
 	"flag"
+	"heuristica/kopt"
+	"heuristica/tsp"
 	"math/rand"
 	"path/filepath"
 	"time"
@@ -18,10 +20,22 @@ func main() {
 	mutRate    := flag.Float64("mut", 0.15, "Probabilidad de mutación (doble-puente)")
 	nParents   := flag.Int("parents", 3, "Número de padres para recombinación (≥3)")
 	convThresh := flag.Int("conv", 3, "Umbral de distancia promedio para reinicio")
+	ls         := flag.String("ls", kopt.Metodo2Opt, "Búsqueda local (2opt, 3opt, lk, clk)")
+	ventana    := flag.Int("ventana", 0, "Tamaño de la ventana de reoptimización exacta final (0 = desactivado, 4..16)")
 	flat       := flag.Bool("flat", false, "Mostrar información en formato plano (sin encabezados)")
 
 	flag.Parse()
 
+	if err := kopt.ValidarMetodo(*ls); err != nil {
+		fmt.Printf("ERROR: %v\n", err)
+		return
+	}
+
+	if err := tsp.ValidateWindow(*ventana); err != nil {
+		fmt.Printf("ERROR: %v\n", err)
+		return
+	}
+
 	archivo := "../Benchmark/berlin52.tsp"
 	args := flag.Args()
 	if len(args) > 0 {
@@ -45,12 +59,12 @@ func main() {
 
 	ma := &MA{
 		dist:       dist,
-		cities:     cities,
 		popSize:    *popSize,
 		maxGen:     *maxGen,
 		mutRate:    *mutRate,
 		nParents:   *nParents,
 		convThresh: *convThresh,
+		ls:         kopt.NuevaBusquedaLocal(*ls, utils.Coordenadas(cities), dist.Dist),
 	}
 
 	start := time.Now()
@@ -58,6 +72,12 @@ func main() {
 	// Ejecutar algoritmo memético
 	bestTour, bestCost := ma.Run()
 
+	// Post-proceso opcional: reoptimización exacta por ventanas
+	if *ventana > 0 {
+		bestTour = tsp.WindowReoptimizeFunc(bestTour, func(a, b int) float64 { return dist[a][b] }, *ventana)
+		bestCost = tourCost(dist, bestTour)
+	}
+
 	elapsed := time.Since(start)
 
 	// Calcular GAP
@@ -81,7 +101,7 @@ func main() {
 			"Benchmark", "Tiempo", "Costo", "Optimo", "GAP MA (%)")
 		fmt.Printf("%s\t%s\t%.4f\t%.0f\t%.2f\n",
 			nombreArchivo, elapsed, bestCost, optimo, gap)
-		fmt.Printf("Configuración MA: Pop=%d, Gen=%d, Mut=%.4f, Padres=%d, Conv=%d\n",
-			*popSize, *maxGen, *mutRate, *nParents, *convThresh)
+		fmt.Printf("Configuración MA: Pop=%d, Gen=%d, Mut=%.4f, Padres=%d, Conv=%d, LS=%s, Ventana=%d\n",
+			*popSize, *maxGen, *mutRate, *nParents, *convThresh, *ls, *ventana)
 	}
 }
\ No newline at end of file
@@ -2,12 +2,12 @@ package main
 
 import (
 	"fmt"
+	"heuristica/kopt"
 	"math"
 	"math/rand"
 	"sort"
 	"tsp-common/models"
 	"tsp-common/utils"
-	"tsp-sa/localsearch"
 )
 
 // Tour es una permutación de índices 0..n-1 sobre el slice de ciudades.
@@ -19,7 +19,7 @@ func (t Tour) clone() Tour {
 	return c
 }
 
-// Matriz de distancias 
+// Matriz de distancias
 type DistMatrix [][]float64
 
 func buildDistMatrix(cities []models.City) DistMatrix {
@@ -34,6 +34,11 @@ func buildDistMatrix(cities []models.City) DistMatrix {
 	return d
 }
 
+// Dist devuelve la distancia entre las ciudades i y j, para las búsquedas de kopt.
+func (d DistMatrix) Dist(i, j int) float64 {
+	return d[i][j]
+}
+
 func tourCost(dist DistMatrix, t Tour) float64 {
 	n := len(t)
 	c := 0.0
@@ -43,15 +48,6 @@ func tourCost(dist DistMatrix, t Tour) float64 {
 	return c
 }
 
-// tourToCities convierte un Tour (índices) al slice de Cities que usa utils.
-func tourToCities(t Tour, cities []models.City) []models.City {
-	out := make([]models.City, len(t))
-	for i, idx := range t {
-		out[i] = cities[idx]
-	}
-	return out
-}
-
 // Distancia entre tours
 // d(t1,t2) = número de aristas en t1 que NO están en t2.
 func tourDist(a, b Tour) int {
@@ -77,26 +73,11 @@ func tourDist(a, b Tour) int {
 	return diff
 }
 
-// Búsqueda local: 2-opt (wrapper sobre localsearch.TwoOpt)
-// TwoOpt trabaja con []models.City, así que convertimos Tour ↔ []City.
-func applyTwoOpt(cities []models.City, t Tour) (Tour, float64) {
-	cityTour := tourToCities(t, cities)
-	improved, cost := localsearch.TwoOpt(cityTour)
-	return citiesToTour(improved, cities), cost
-}
-
-// citiesToTour convierte []City de vuelta a Tour (índices) usando el ID de cada ciudad.
-func citiesToTour(cityTour []models.City, cities []models.City) Tour {
-	// índice inverso: ID → posición en el slice original
-	idToIdx := make(map[int]int, len(cities))
-	for i, c := range cities {
-		idToIdx[c.ID] = i
-	}
-	t := make(Tour, len(cityTour))
-	for i, c := range cityTour {
-		t[i] = idToIdx[c.ID]
-	}
-	return t
+// Búsqueda local elegida con -ls. kopt trabaja directo sobre la
+// permutación de índices, así que no hace falta convertir Tour ↔ []City.
+func applyLocalSearch(ls *kopt.BusquedaLocal, t Tour) (Tour, float64) {
+	improved, cost := ls.Optimizar(t)
+	return Tour(improved), cost
 }
 
 // Recombinación respetuosa
@@ -288,14 +269,14 @@ func doubleBridge(t Tour) Tour {
 	return result
 }
 
-// Individuo 
+// Individuo
 type Individual struct {
 	tour Tour
 	cost float64
 }
 
 // Inicialización
-func initPopulation(dist DistMatrix, cities []models.City, size int) []Individual {
+func initPopulation(dist DistMatrix, size int, ls *kopt.BusquedaLocal) []Individual {
 	n := len(dist)
 	perm := make([]int, n)
 	for i := range perm {
@@ -308,7 +289,7 @@ func initPopulation(dist DistMatrix, cities []models.City, size int) []Individua
 		attempts++
 		rand.Shuffle(n, func(i, j int) { perm[i], perm[j] = perm[j], perm[i] })
 		t := Tour(append([]int{}, perm...))
-		t, _ = applyTwoOpt(cities, t)
+		t, _ = applyLocalSearch(ls, t)
 		key := fmt.Sprint(t)
 		if !seen[key] {
 			seen[key] = true
@@ -322,16 +303,16 @@ func initPopulation(dist DistMatrix, cities []models.City, size int) []Individua
 // Algoritmo Memético
 type MA struct {
 	dist       DistMatrix
-	cities     []models.City
 	popSize    int
 	maxGen     int
 	mutRate    float64
-	nParents   int // ≥ 3 (requerimiento del proyecto)
-	convThresh int // distancia promedio mínima antes de reiniciar
+	nParents   int                 // ≥ 3 (requerimiento del proyecto)
+	convThresh int                 // distancia promedio mínima antes de reiniciar
+	ls         *kopt.BusquedaLocal // búsqueda local elegida con -ls
 }
 
 func (ma *MA) Run() (Tour, float64) {
-	pop := initPopulation(ma.dist, ma.cities, ma.popSize)
+	pop := initPopulation(ma.dist, ma.popSize, ma.ls)
 	best := pop[0]
 
 	for gen := 0; gen < ma.maxGen; gen++ {
@@ -352,7 +333,7 @@ func (ma *MA) Run() (Tour, float64) {
 
 		// Mejora local post-recombinación → intensificación (núcleo del AM)
 		var childCost float64
-		child, childCost = applyTwoOpt(ma.cities, child)
+		child, childCost = applyLocalSearch(ma.ls, child)
 
 		// Reemplazo elitista: entra si mejora al peor
 		worst := len(pop) - 1
@@ -392,9 +373,9 @@ func (ma *MA) restart(pop []Individual) []Individual {
 	newPop := []Individual{pop[0]} // conservar el mejor
 	for i := 1; i < len(pop); i++ {
 		t := doubleBridge(pop[i].tour.clone())
-		t, _ = applyTwoOpt(ma.cities, t)
+		t, _ = applyLocalSearch(ma.ls, t)
 		newPop = append(newPop, Individual{tour: t, cost: tourCost(ma.dist, t)})
 	}
 	sort.Slice(newPop, func(i, j int) bool { return newPop[i].cost < newPop[j].cost })
 	return newPop
-}
\ No newline at end of file
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
+	"tsp-common/kdtree"
 	"tsp-common/models"
 	"tsp-common/utils"
 )
@@ -19,6 +20,11 @@ type ACO struct {
 	q           float64
 	pheromone   [][]float64
 	heuristic   [][]float64
+
+	// Lista de candidatos: con numCand > 0 cada hormiga elige solo entre las
+	// numCand ciudades no visitadas más cercanas, consultadas al árbol k-d
+	numCand int
+	arbol   *kdtree.Arbol
 }
 
 type Ant struct {
@@ -39,7 +45,9 @@ func buildDistMatrix(cities []models.City) [][]float64 {
 	return d
 }
 
-func NewACO(cities []models.City, numAnts, numIter int, alpha, beta, evaporation, q float64) *ACO {
+// NewACO prepara la colonia. numCand es el tamaño de la lista de candidatos
+// (0 = considerar todas las ciudades no visitadas).
+func NewACO(cities []models.City, numAnts, numIter int, alpha, beta, evaporation, q float64, numCand int) *ACO {
 	n := len(cities)
 	dist := buildDistMatrix(cities)
 
@@ -74,6 +82,8 @@ func NewACO(cities []models.City, numAnts, numIter int, alpha, beta, evaporation
 		q:           q,
 		pheromone:   pheromone,
 		heuristic:   heuristic,
+		numCand:     numCand,
+		arbol:       kdtree.Nuevo(cities),
 	}
 }
 
@@ -110,27 +120,40 @@ func (aco *ACO) buildAntSolution() Ant {
 	start := rand.Intn(n)
 	ant.path = append(ant.path, start)
 	ant.visited[start] = true
+	aco.arbol.Restaurar()
+	aco.arbol.Eliminar(start)
 
 	curr := start
 	for step := 1; step < n; step++ {
 		next := aco.selectNextCity(curr, ant.visited)
 		ant.path = append(ant.path, next)
 		ant.visited[next] = true
+		aco.arbol.Eliminar(next)
 		curr = next
 	}
 
 	// Calcular costo del recorrido completo
+	ant.cost = aco.tourCost(ant.path)
+
+	return ant
+}
+
+// tourCost suma las aristas del recorrido cerrado
+func (aco *ACO) tourCost(path []int) float64 {
+	n := len(path)
 	cost := 0.0
 	for i := 0; i < n-1; i++ {
-		cost += aco.dist[ant.path[i]][ant.path[i+1]]
+		cost += aco.dist[path[i]][path[i+1]]
 	}
-	cost += aco.dist[ant.path[n-1]][ant.path[0]]
-	ant.cost = cost
-
-	return ant
+	cost += aco.dist[path[n-1]][path[0]]
+	return cost
 }
 
 func (aco *ACO) selectNextCity(curr int, visited []bool) int {
+	if aco.numCand > 0 {
+		return aco.selectFromCandidates(curr, aco.arbol.KCercanos(aco.cities[curr], aco.numCand))
+	}
+
 	n := len(aco.cities)
 	probs := make([]float64, n)
 	sumProbs := 0.0
@@ -175,6 +198,34 @@ func (aco *ACO) selectNextCity(curr int, visited []bool) int {
 	return -1
 }
 
+// selectFromCandidates aplica la ruleta solo sobre la lista de candidatos
+// (ciudades no visitadas, de la más cercana a la más lejana).
+func (aco *ACO) selectFromCandidates(curr int, cands []int) int {
+	probs := make([]float64, len(cands))
+	sumProbs := 0.0
+	for i, c := range cands {
+		probs[i] = math.Pow(aco.pheromone[curr][c], aco.alpha) * math.Pow(aco.heuristic[curr][c], aco.beta)
+		sumProbs += probs[i]
+	}
+
+	// Fallback si la suma de probabilidades es muy cercana a 0 debido al underflow
+	if sumProbs <= 0.0 {
+		return cands[rand.Intn(len(cands))]
+	}
+
+	r := rand.Float64() * sumProbs
+	acc := 0.0
+	for i, c := range cands {
+		acc += probs[i]
+		if r <= acc {
+			return c
+		}
+	}
+
+	// Fallback para problemas de precisión
+	return cands[len(cands)-1]
+}
+
 func (aco *ACO) updatePheromones(ants []Ant) {
 	n := len(aco.cities)
 
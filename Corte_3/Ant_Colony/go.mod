@@ -3,4 +3,7 @@ module aco
 go 1.25.6
 
 require tsp-common v0.0.0
-replace tsp-common => ../common
+replace tsp-common => ../../Corte_2/common
+
+require heuristica v0.0.0
+replace heuristica => ../../Corte_1/Heuristica
@@ -3,6 +3,7 @@ package main
 import (
 	"flag"
 	"fmt"
+	"heuristica/tsp"
 	"math/rand"
 	"path/filepath"
 	"time"
@@ -19,10 +20,17 @@ func main() {
 	beta := flag.Float64("beta", 5.0, "Parámetro que pesa la información heurística (1/d)")
 	evap := flag.Float64("evap", 0.5, "Tasa de evaporación de feromona (rho)")
 	q := flag.Float64("q", 100.0, "Constante para el depósito de feromona (Q)")
+	cand := flag.Int("cand", 20, "Tamaño de la lista de candidatos por ciudad (0 = todas las no visitadas)")
+	ventana := flag.Int("ventana", 0, "Tamaño de la ventana de reoptimización exacta final (0 = desactivado, 4..16)")
 	flat := flag.Bool("flat", false, "Mostrar información en formato plano (sin encabezados)")
 
 	flag.Parse()
 
+	if err := tsp.ValidateWindow(*ventana); err != nil {
+		fmt.Printf("ERROR: %v\n", err)
+		return
+	}
+
 	archivo := "../Benchmark/berlin52.tsp"
 	args := flag.Args()
 	if len(args) > 0 {
@@ -42,12 +50,18 @@ func main() {
 		return
 	}
 
-	aco := NewACO(cities, *numAnts, *numIter, *alpha, *beta, *evap, *q)
+	aco := NewACO(cities, *numAnts, *numIter, *alpha, *beta, *evap, *q, *cand)
 
 	start := time.Now()
 
 	bestTour, bestCost := aco.Run()
 
+	// Post-proceso opcional: reoptimización exacta por ventanas
+	if *ventana > 0 {
+		bestTour = tsp.WindowReoptimizeFunc(bestTour, func(a, b int) float64 { return aco.dist[a][b] }, *ventana)
+		bestCost = aco.tourCost(bestTour)
+	}
+
 	elapsed := time.Since(start)
 
 	optimo := utils.GetOptimalCost(archivo)
@@ -69,7 +83,7 @@ func main() {
 			"Benchmark", "Tiempo", "Costo", "Optimo", "GAP ACO (%)")
 		fmt.Printf("%s\t%s\t%.4f\t%.0f\t%.2f\n",
 			nombreArchivo, elapsed, bestCost, optimo, gap)
-		fmt.Printf("Configuración ACO: Hormigas=%d, Gen=%d, Alpha=%.2f, Beta=%.2f, Evap=%.2f, Q=%.2f\n",
-			*numAnts, *numIter, *alpha, *beta, *evap, *q)
+		fmt.Printf("Configuración ACO: Hormigas=%d, Gen=%d, Alpha=%.2f, Beta=%.2f, Evap=%.2f, Q=%.2f, Cand=%d, Ventana=%d\n",
+			*numAnts, *numIter, *alpha, *beta, *evap, *q, *cand, *ventana)
 	}
 }
@@ -1,115 +1,103 @@
 package geneticalgorithm
 
 import (
-	"math"
+	"fmt"
+	"heuristica/tsp"
 	"tsp-ds/models"
 	"tsp-ds/utils"
 )
 
 // FarthestInsertion builds a tour using the farthest insertion heuristic.
-// Adapted from Corte_1/Heuristica/tsp/insertion.go to work with []models.City.
+// It delegates to the shared O(n^2) implementation in Corte_1/Heuristica;
+// distances are computed on demand, so no n x n matrix is allocated.
 // Returns a permutation of indices [0..n-1].
 func FarthestInsertion(cities []models.City) []int {
-	n := len(cities)
-	if n < 3 {
-		perm := make([]int, n)
-		for i := range perm {
-			perm[i] = i
-		}
-		return perm
-	}
+	return tsp.FarthestInsertionFunc(len(cities), func(i, j int) float64 {
+		return utils.DistanciaEuclidiana(cities[i], cities[j])
+	})
+}
 
-	// Precompute distance matrix for efficiency (O(n^2) memory, avoids repeated sqrt)
-	dist := make([][]float64, n)
-	for i := 0; i < n; i++ {
-		dist[i] = make([]float64, n)
-	}
-	for i := 0; i < n; i++ {
-		for j := i + 1; j < n; j++ {
-			d := utils.DistanciaEuclidiana(cities[i], cities[j])
-			dist[i][j] = d
-			dist[j][i] = d
-		}
-	}
+// Heuristics selectable for the seed individual of initPopulation (-init).
+const (
+	SeedFarthest     = "farthest"
+	SeedGreedy       = "greedy"
+	SeedHilbert      = "hilbert"
+	SeedSierpinski   = "sierpinski"
+	SeedChristofides = "christofides"
+	SeedDoubleTree   = "double-tree"
+)
 
-	// Start with the two farthest cities
-	maxDist := -1.0
-	var city1, city2 int
-	for i := 0; i < n; i++ {
-		for j := i + 1; j < n; j++ {
-			if dist[i][j] > maxDist {
-				maxDist = dist[i][j]
-				city1, city2 = i, j
-			}
-		}
-	}
+// Seeds lists the available seed heuristics.
+var Seeds = []string{SeedFarthest, SeedGreedy, SeedHilbert, SeedSierpinski, SeedChristofides, SeedDoubleTree}
 
-	// Find the city farthest from these two (max of min distances)
-	maxMinDist := -1.0
-	city3 := -1
-	for i := 0; i < n; i++ {
-		if i == city1 || i == city2 {
-			continue
-		}
-		minDist := math.Min(dist[city1][i], dist[city2][i])
-		if minDist > maxMinDist {
-			maxMinDist = minDist
-			city3 = i
+// ValidateSeed returns an error if the name is not a seed heuristic.
+func ValidateSeed(seed string) error {
+	for _, s := range Seeds {
+		if s == seed {
+			return nil
 		}
 	}
+	return fmt.Errorf("heurística de semilla desconocida %q (opciones: %v)", seed, Seeds)
+}
 
-	// Initialize tour with these 3 cities
-	tour := []int{city1, city2, city3}
-	inTour := make([]bool, n)
-	inTour[city1] = true
-	inTour[city2] = true
-	inTour[city3] = true
+// GreedyEdge builds a tour using the greedy edge heuristic, also from the
+// shared implementation in Corte_1/Heuristica (candidates from k-nearest
+// lists, so memory stays O(n)).
+func GreedyEdge(cities []models.City) []int {
+	return tsp.GreedyEdgeFunc(len(cities), func(i, j int) float64 {
+		return utils.DistanciaEuclidiana(cities[i], cities[j])
+	}, coordinates(cities), tsp.GreedyNeighbors)
+}
 
-	// Insert remaining cities one by one
-	for len(tour) < n {
-		// Find the city farthest from the current tour
-		farthestCity := -1
-		farthestDist := -1.0
+// HilbertCurve orders the cities along the Hilbert curve in O(n log n), the
+// only seed that stays fast on instances of 100k+ cities.
+func HilbertCurve(cities []models.City) []int {
+	return tsp.HilbertOrder(coordinates(cities))
+}
 
-		for c := 0; c < n; c++ {
-			if inTour[c] {
-				continue
-			}
-			// Minimum distance from c to any city in the tour
-			minDist := math.MaxFloat64
-			for _, t := range tour {
-				if dist[c][t] < minDist {
-					minDist = dist[c][t]
-				}
-			}
-			if minDist > farthestDist {
-				farthestDist = minDist
-				farthestCity = c
-			}
-		}
+// SierpinskiCurve orders the cities along the Sierpinski curve in O(n log n).
+func SierpinskiCurve(cities []models.City) []int {
+	return tsp.SierpinskiOrder(coordinates(cities))
+}
 
-		// Find the best position to insert (minimizes cost increase)
-		bestPos := -1
-		bestCost := math.MaxFloat64
+// Christofides builds a tour with the shared Christofides construction
+// (MST + minimum-weight matching of the odd vertices + shortcut Euler tour).
+func Christofides(cities []models.City) []int {
+	return tsp.ChristofidesFunc(len(cities), func(i, j int) float64 {
+		return utils.DistanciaEuclidiana(cities[i], cities[j])
+	}, coordinates(cities))
+}
 
-		for pos := 0; pos < len(tour); pos++ {
-			i := tour[pos]
-			j := tour[(pos+1)%len(tour)]
-			costIncrease := dist[i][farthestCity] + dist[farthestCity][j] - dist[i][j]
-			if costIncrease < bestCost {
-				bestCost = costIncrease
-				bestPos = pos
-			}
-		}
+// DoubleTree builds a tour by shortcutting a preorder walk of the MST.
+func DoubleTree(cities []models.City) []int {
+	return tsp.DoubleTreeFunc(len(cities), func(i, j int) float64 {
+		return utils.DistanciaEuclidiana(cities[i], cities[j])
+	}, coordinates(cities))
+}
 
-		// Insert the city after bestPos
-		newTour := make([]int, len(tour)+1)
-		copy(newTour, tour[:bestPos+1])
-		newTour[bestPos+1] = farthestCity
-		copy(newTour[bestPos+2:], tour[bestPos+1:])
-		tour = newTour
-		inTour[farthestCity] = true
+func coordinates(cities []models.City) [][2]float64 {
+	coords := make([][2]float64, len(cities))
+	for i, c := range cities {
+		coords[i] = [2]float64{c.X, c.Y}
 	}
+	return coords
+}
 
-	return tour
+// SeedTour builds the seed individual with the given heuristic
+// (Farthest Insertion if empty).
+func SeedTour(cities []models.City, seed string) []int {
+	switch seed {
+	case SeedGreedy:
+		return GreedyEdge(cities)
+	case SeedHilbert:
+		return HilbertCurve(cities)
+	case SeedSierpinski:
+		return SierpinskiCurve(cities)
+	case SeedChristofides:
+		return Christofides(cities)
+	case SeedDoubleTree:
+		return DoubleTree(cities)
+	default:
+		return FarthestInsertion(cities)
+	}
 }
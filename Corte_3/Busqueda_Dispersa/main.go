@@ -3,6 +3,8 @@ package main
 import (
 	"flag"
 	"fmt"
+	"heuristica/kopt"
+	"heuristica/tsp"
 	"math/rand"
 	"path/filepath"
 	"time"
@@ -22,11 +24,29 @@ func main() {
 	stag := flag.Int("stag", 200, "Generaciones sin mejora antes de parar (0 = desactivado)")
 	relink := flag.Float64("relink", 0.5, "Porcentaje de pares a reenlazar en cada generación (ej. 0.5 para 50%)")
 	divthresh := flag.Int("divthresh", 5, "Distancia mínima (aristas) para aceptar un individuo en la población (ej. 5)")
+	ls := flag.String("ls", kopt.Metodo2Opt, "Busqueda local aplicada a cada hijo (2opt, 3opt, lk, clk)")
+	semilla := flag.String("init", geneticalgorithm.SeedFarthest, "Heuristica constructiva del individuo semilla (farthest, greedy, hilbert, sierpinski, christofides, double-tree)")
+	ventana := flag.Int("ventana", 0, "Tamaño de la ventana de reoptimizacion exacta final (0 = desactivado, 4..16)")
 	flat := flag.Bool("flat", false, "Mostrar informacion en formato plano (sin encabezados)")
 
 	// Parsear los argumentos de la linea de comandos
 	flag.Parse()
 
+	if err := kopt.ValidarMetodo(*ls); err != nil {
+		fmt.Printf("ERROR: %v\n", err)
+		return
+	}
+
+	if err := geneticalgorithm.ValidateSeed(*semilla); err != nil {
+		fmt.Printf("ERROR: %v\n", err)
+		return
+	}
+
+	if err := tsp.ValidateWindow(*ventana); err != nil {
+		fmt.Printf("ERROR: %v\n", err)
+		return
+	}
+
 	// Ruta por defecto o por argumento
 	archivo := "../Benchmark/berlin52.tsp"
 	args := flag.Args()
@@ -49,8 +69,10 @@ func main() {
 		MutationRate:    *mut,
 		TournamentSize:  *tourn,
 		StagnationLimit: *stag,
+		Seed:            *semilla,
 		RelinkPct:       *relink,
 		DivThreshold:    *divthresh,
+		LocalSearch:     *ls,
 	}
 
 	start := time.Now()
@@ -58,6 +80,12 @@ func main() {
 	// 2. Ejecutar Algoritmo Memético (antes Genético)
 	result := solver.GeneticAlgorithmSolver(ciudades, configGA)
 
+	// Post-proceso opcional: reoptimización exacta por ventanas
+	if *ventana > 0 {
+		result.BestTour = tsp.WindowReoptimizeSlice(result.BestTour, utils.DistanciaEuclidiana, *ventana)
+		result.BestCost = utils.CalcularCostoTotal(result.BestTour)
+	}
+
 	elapsed := time.Since(start)
 
 	// 3. Calculo del GAP
@@ -71,7 +99,7 @@ func main() {
 	nombreArchivo := filepath.Base(archivo)
 
 	if *flat {
-		fmt.Printf("%s,%.4f,%s,%.0f,%.2f,%d,%d,%.4f,%d,%d,%d,%d,%d,%d,%s\n",
+		fmt.Printf("%s,%.4f,%s,%.0f,%.2f,%d,%d,%.4f,%d,%d,%.2f,%d,%d,%d,%s\n",
 			nombreArchivo, result.BestCost, elapsed, optimo, gapGA,
 			*pop, *gen, *mut, *tourn, *stag, *relink, *divthresh,
 			result.LastImproveGen, result.TotalGens, result.StopReason)
@@ -80,8 +108,8 @@ func main() {
 			"Benchmark", "Tiempo", "Costo", "Optimo", "GAP AM (%)") 
 		fmt.Printf("%s\t%s\t%.4f\t%.0f\t%.2f\n",
 			nombreArchivo, elapsed, result.BestCost, optimo, gapGA)
-		fmt.Printf("Configuracion AM: Pop=%d, Gen=%d, Mut=%.4f, Tourn=%d, Stag=%d, Relink=%.2f, DivThresh=%d\n",
-			*pop, *gen, *mut, *tourn, *stag, *relink, *divthresh)
+		fmt.Printf("Configuracion AM: Pop=%d, Gen=%d, Mut=%.4f, Tourn=%d, Stag=%d, Relink=%.2f, DivThresh=%d, LS=%s, Init=%s, Ventana=%d\n",
+			*pop, *gen, *mut, *tourn, *stag, *relink, *divthresh, *ls, *semilla, *ventana)
 		fmt.Printf("Convergencia: ultima mejora en gen %d, parada en gen %d por %s\n",
 			result.LastImproveGen, result.TotalGens, result.StopReason)
 	}
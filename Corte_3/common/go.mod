@@ -1,3 +0,0 @@
-module tsp-common
-
-go 1.23
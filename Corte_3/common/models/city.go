@@ -1,7 +0,0 @@
-package models
-
-type City struct {
-	ID int
-	X  float64
-	Y  float64
-}
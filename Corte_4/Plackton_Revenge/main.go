@@ -3,6 +3,8 @@ package main
 import (
 	"flag"
 	"fmt"
+	"heuristica/kopt"
+	"heuristica/tsp"
 	"math/rand"
 	"path/filepath"
 	"time"
@@ -24,11 +26,31 @@ func main() {
 	bloom := flag.Float64("bloom", 0.1, "Porcentaje de florecimiento (BloomPct)")
 	tfreq := flag.Int("tfreq", 50, "Frecuencia de turbulencia en iteraciones (T)")
 	tmu := flag.Float64("tmu", 0.2, "Intensidad de turbulencia / Fraccion perturbada (Mu)")
+	ls := flag.String("ls", kopt.MetodoNinguno, "Busqueda local de los hijos del florecimiento (none, 2opt, 3opt, lk, clk)")
+	semilla := flag.String("init", plancton.SemillaFarthest, "Heuristica constructiva del plancton Alfa (farthest, hilbert, sierpinski, christofides, double-tree)")
+	ventana := flag.Int("ventana", 0, "Tamaño de la ventana de reoptimizacion exacta final (0 = desactivado, 4..16)")
 	flat := flag.Bool("flat", false, "Mostrar informacion en formato plano (sin encabezados)")
 
 	// Parsear los argumentos de la linea de comandos
 	flag.Parse()
 
+	if *ls != kopt.MetodoNinguno {
+		if err := kopt.ValidarMetodo(*ls); err != nil {
+			fmt.Printf("ERROR: %v o %s\n", err, kopt.MetodoNinguno)
+			return
+		}
+	}
+
+	if err := plancton.ValidarSemilla(*semilla); err != nil {
+		fmt.Printf("ERROR: %v\n", err)
+		return
+	}
+
+	if err := tsp.ValidateWindow(*ventana); err != nil {
+		fmt.Printf("ERROR: %v\n", err)
+		return
+	}
+
 	// Ruta por defecto o por argumento
 	archivo := "../Benchmark/berlin52.tsp"
 	args := flag.Args()
@@ -52,14 +74,22 @@ func main() {
 		Alpha:      *alpha,
 		DeltaInit:  *delta,
 		Gamma:      *gamma,
-		BloomPct:   *bloom,
-		TurbFreq:   *tfreq,
-		TurbIntens: *tmu,
+		BloomPct:    *bloom,
+		LocalSearch: *ls,
+		TurbFreq:    *tfreq,
+		TurbIntens:  *tmu,
+		Semilla:     *semilla,
 	}
 
 	// 3. Ejecutar OFP y medir el tiempo
 	start := time.Now()
 	result := plancton.EjecutarOFP(ciudades, configOFP)
+
+	// Post-proceso opcional: reoptimización exacta por ventanas
+	if *ventana > 0 {
+		result.BestTour = tsp.WindowReoptimizeSlice(result.BestTour, utils.DistanciaEuclidiana, *ventana)
+		result.BestCost = utils.CalcularCostoTotal(result.BestTour)
+	}
 	elapsed := time.Since(start)
 
 	// 4. Calculo del GAP con tu BKS
@@ -84,8 +114,8 @@ func main() {
 			"Benchmark", "Tiempo", "Costo", "Optimo", "GAP OFP (%)")
 		fmt.Printf("%s\t%s\t%.4f\t%.0f\t%.2f\n",
 			nombreArchivo, elapsed, result.BestCost, optimo, gapOFP)
-		fmt.Printf("Config OFP: Pop=%d, Iter=%d, Alpha=%.2f, Delta=%.2f, Gamma=%.2f, Bloom=%.2f, TFreq=%d, TMu=%.2f\n",
-			*pop, *iter, *alpha, *delta, *gamma, *bloom, *tfreq, *tmu)
+		fmt.Printf("Config OFP: Pop=%d, Iter=%d, Alpha=%.2f, Delta=%.2f, Gamma=%.2f, Bloom=%.2f, TFreq=%d, TMu=%.2f, LS=%s, Init=%s, Ventana=%d\n",
+			*pop, *iter, *alpha, *delta, *gamma, *bloom, *tfreq, *tmu, *ls, *semilla, *ventana)
 		fmt.Printf("Convergencia: ultima mejora en iteracion %d\n", result.LastImproveGen)
 	}
 }
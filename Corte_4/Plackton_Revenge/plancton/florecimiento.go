@@ -1,6 +1,7 @@
 package plancton
 
 import (
+	"heuristica/kopt"
 	"math/rand"
 	"sort"
 	"tsp/models"
@@ -8,8 +9,9 @@ import (
 )
 
 // AplicarFlorecimiento toma la fracción de élite (B) y genera descendientes
-// con una perturbación mínima. Retorna la población incrementada.
-func AplicarFlorecimiento(poblacion []Plancton, B float64, cities []models.City) []Plancton {
+// con una perturbación mínima, refinados con la búsqueda local ls (con
+// kopt.MetodoNinguno quedan tal cual). Retorna la población incrementada.
+func AplicarFlorecimiento(poblacion []Plancton, B float64, ls *kopt.BusquedaLocal, cities []models.City) []Plancton {
 	nPop := len(poblacion)
 	if nPop == 0 {
 		return poblacion
@@ -47,9 +49,10 @@ func AplicarFlorecimiento(poblacion []Plancton, B float64, cities []models.City)
 			b--
 		}
 
+		tourHijo, costoHijo := ls.Optimizar(tourHijo)
 		hijo := Plancton{
 			Tour: tourHijo,
-			Cost: utils.CalcularCostoPermutacion(tourHijo, cities),
+			Cost: costoHijo,
 		}
 		hijos = append(hijos, hijo)
 	}
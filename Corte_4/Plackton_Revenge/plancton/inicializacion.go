@@ -1,118 +1,86 @@
 package plancton
 
 import (
-	"math"
+	"fmt"
+	"heuristica/tsp"
 	"math/rand"
 	"tsp/utils"
 )
 
-// FarthestInsertion construye un tour inicial de alta calidad basado en distancias máximas.
-func FarthestInsertion(oceano utils.Oceano) []int {
-	n := len(oceano)
-	if n < 3 {
-		perm := make([]int, n)
-		for i := range perm {
-			perm[i] = i
-		}
-		return perm
-	}
+// Heurísticas seleccionables para el plancton "Alfa" con la opción -init.
+const (
+	SemillaFarthest     = "farthest"
+	SemillaHilbert      = "hilbert"
+	SemillaSierpinski   = "sierpinski"
+	SemillaChristofides = "christofides"
+	SemillaDoubleTree   = "double-tree"
+)
 
-	// 1. Iniciar con las dos ciudades más lejanas entre sí
-	maxDist := -1.0
-	var city1, city2 int
-	for i := 0; i < n; i++ {
-		for j := i + 1; j < n; j++ {
-			d := utils.DistanciaEuclidiana(oceano[i], oceano[j])
-			if d > maxDist {
-				maxDist = d
-				city1, city2 = i, j
-			}
-		}
-	}
+// Semillas lista las heurísticas disponibles para el plancton "Alfa".
+var Semillas = []string{SemillaFarthest, SemillaHilbert, SemillaSierpinski, SemillaChristofides, SemillaDoubleTree}
 
-	// 2. Encontrar la tercera ciudad más lejana a estas dos
-	maxMinDist := -1.0
-	city3 := -1
-	for i := 0; i < n; i++ {
-		if i == city1 || i == city2 {
-			continue
-		}
-		minD := math.Min(utils.DistanciaEuclidiana(oceano[city1], oceano[i]), 
-						 utils.DistanciaEuclidiana(oceano[city2], oceano[i]))
-		if minD > maxMinDist {
-			maxMinDist = minD
-			city3 = i
+// ValidarSemilla devuelve un error si el nombre no corresponde a ninguna heurística.
+func ValidarSemilla(semilla string) error {
+	for _, s := range Semillas {
+		if s == semilla {
+			return nil
 		}
 	}
+	return fmt.Errorf("heurística de semilla desconocida %q (opciones: %v)", semilla, Semillas)
+}
 
-	tour := []int{city1, city2, city3}
-	enTour := make([]bool, n)
-	enTour[city1], enTour[city2], enTour[city3] = true, true, true
-
-	// 3. Insertar el resto de las ciudades una por una
-	for len(tour) < n {
-		farthestCity := -1
-		farthestDist := -1.0
-
-		// Buscar la ciudad más lejana al tour actual
-		for c := 0; c < n; c++ {
-			if enTour[c] {
-				continue
-			}
-			minDistAlTour := math.MaxFloat64
-			for _, t := range tour {
-				d := utils.DistanciaEuclidiana(oceano[c], oceano[t])
-				if d < minDistAlTour {
-					minDistAlTour = d
-				}
-			}
-			if minDistAlTour > farthestDist {
-				farthestDist = minDistAlTour
-				farthestCity = c
-			}
-		}
-
-		// Encontrar la mejor posición para insertar (mínimo incremento de costo)
-		bestPos := -1
-		minIncremento := math.MaxFloat64
+// FarthestInsertion construye un tour inicial de alta calidad basado en distancias máximas.
+// Usa la implementación O(n^2) compartida de Corte_1/Heuristica, calculando
+// las distancias a demanda.
+func FarthestInsertion(oceano utils.Oceano) []int {
+	return tsp.FarthestInsertionFunc(len(oceano), distancia(oceano))
+}
 
-		for pos := 0; pos < len(tour); pos++ {
-			c1 := oceano[tour[pos]]
-			c2 := oceano[tour[(pos+1)%len(tour)]]
-			nueva := oceano[farthestCity]
-			
-			incremento := utils.DistanciaEuclidiana(c1, nueva) + 
-						  utils.DistanciaEuclidiana(nueva, c2) - 
-						  utils.DistanciaEuclidiana(c1, c2)
-			
-			if incremento < minIncremento {
-				minIncremento = incremento
-				bestPos = pos
-			}
-		}
+// distancia devuelve la distancia euclidiana entre ciudades por índice
+func distancia(oceano utils.Oceano) func(i, j int) float64 {
+	return func(i, j int) float64 {
+		return utils.DistanciaEuclidiana(oceano[i], oceano[j])
+	}
+}
 
-		// Insertar la ciudad
-		nuevoTour := make([]int, 0, len(tour)+1)
-		nuevoTour = append(nuevoTour, tour[:bestPos+1]...)
-		nuevoTour = append(nuevoTour, farthestCity)
-		nuevoTour = append(nuevoTour, tour[bestPos+1:]...)
-		tour = nuevoTour
-		enTour[farthestCity] = true
+// TourSemilla construye el tour del plancton "Alfa". Las curvas ordenan las
+// ciudades en O(n log n), lo que permite arrancar con 100k+ ciudades;
+// Christofides y double-tree parten del árbol de expansión mínima. Un
+// nombre vacío equivale a Farthest Insertion.
+func TourSemilla(oceano utils.Oceano, semilla string) []int {
+	switch semilla {
+	case SemillaHilbert:
+		return tsp.HilbertOrder(coordenadas(oceano))
+	case SemillaSierpinski:
+		return tsp.SierpinskiOrder(coordenadas(oceano))
+	case SemillaChristofides:
+		return tsp.ChristofidesFunc(len(oceano), distancia(oceano), coordenadas(oceano))
+	case SemillaDoubleTree:
+		return tsp.DoubleTreeFunc(len(oceano), distancia(oceano), coordenadas(oceano))
+	default:
+		return FarthestInsertion(oceano)
 	}
+}
 
-	return tour
+func coordenadas(oceano utils.Oceano) [][2]float64 {
+	coords := make([][2]float64, len(oceano))
+	for i, c := range oceano {
+		coords[i] = [2]float64{c.X, c.Y}
+	}
+	return coords
 }
 
 // InicializarPoblacion crea la población inicial de planctones.
-// Incluye una semilla de Farthest Insertion y el resto aleatorios para mantener diversidad.
-func InicializarPoblacion(oceano utils.Oceano, nPop int) []Plancton {
+// Incluye una semilla constructiva (ver TourSemilla) y el resto aleatorios
+// para mantener diversidad.
+func InicializarPoblacion(oceano utils.Oceano, nPop int, semilla string) []Plancton {
 	poblacion := make([]Plancton, 0, nPop)
 
-	// 1. Crear el plancton "Alfa" con Farthest Insertion
-	tourFI := FarthestInsertion(oceano)
+	// 1. Crear el plancton "Alfa" con la heurística constructiva
+	tourAlfa := TourSemilla(oceano, semilla)
 	poblacion = append(poblacion, Plancton{
-		Tour: tourFI,
-		Cost: utils.CalcularCostoPermutacion(tourFI, oceano),
+		Tour: tourAlfa,
+		Cost: utils.CalcularCostoPermutacion(tourAlfa, oceano),
 	})
 
 	// 2. Llenar el resto de la población con permutaciones aleatorias
@@ -126,4 +94,4 @@ func InicializarPoblacion(oceano utils.Oceano, nPop int) []Plancton {
 	}
 
 	return poblacion
-}
\ No newline at end of file
+}
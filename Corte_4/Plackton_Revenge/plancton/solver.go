@@ -1,6 +1,7 @@
 package plancton
 
 import (
+	"heuristica/kopt"
 	"sort"
 	"tsp/models"
 	"tsp/utils"
@@ -19,7 +20,10 @@ func EjecutarOFP(oceano utils.Oceano, config OFPConfig) OFPResult {
 	nCities := len(oceano)
 	
 	// 1. Inicialización
-	poblacion := InicializarPoblacion(oceano, config.PopSize)
+	poblacion := InicializarPoblacion(oceano, config.PopSize, config.Semilla)
+
+	// Búsqueda local del florecimiento, con las listas de vecinos de la instancia
+	ls := kopt.NuevaBusquedaLocal(config.LocalSearch, coordenadas(oceano), distancia(oceano))
 	
 	// Rastrear el mejor global
 	mejorGlobal := Plancton{
@@ -46,7 +50,7 @@ func EjecutarOFP(oceano utils.Oceano, config OFPConfig) OFPResult {
 		}
 
 		// OPERADOR 3: Florecimiento / Bloom (Intensificación)
-		poblacion = AplicarFlorecimiento(poblacion, config.BloomPct, oceano)
+		poblacion = AplicarFlorecimiento(poblacion, config.BloomPct, ls, oceano)
 
 		// OPERADOR 4: Turbulencia (Diversificación periódica)
 		if t > 0 && t%config.TurbFreq == 0 {
@@ -14,11 +14,15 @@ type OFPConfig struct {
 	Gamma     float64 // Γ: Tasa de decaimiento quimiotáctico (0.0 a 1.0). Para la fórmula: δ_t = δ_0 * (Γ^t)
 
 	// Operador 3 - Florecimiento (Bloom)
-	BloomPct float64 // B: Porcentaje de florecimiento (Fracción élite que se reproduce, ej. 0.1 para 10%)
+	BloomPct    float64 // B: Porcentaje de florecimiento (Fracción élite que se reproduce, ej. 0.1 para 10%)
+	LocalSearch string  // Búsqueda local aplicada a cada hijo del florecimiento ("none", "2opt", "3opt", "lk", "clk")
 
 	// Operador 4 - Turbulencia
 	TurbFreq   int     // T: Frecuencia de turbulencia (Cada cuántas iteraciones ocurre la dispersión)
 	TurbIntens float64 // μ: Intensidad de turbulencia (Fracción de la población que será perturbada)
+
+	// Inicialización
+	Semilla string // Heurística del plancton "Alfa" ("farthest", "hilbert", "sierpinski")
 }
 
 // Plancton representa a un individuo (solución candidata) en el entorno.